@@ -18,6 +18,9 @@ import (
 
 var geminiClient = newGeminiHTTPClient()
 
+// geminiBaseURL: endpoint Gemini API, diganti server lokal saat test.
+var geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
 func newGeminiHTTPClient() *http.Client {
 	tr := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
@@ -90,6 +93,18 @@ type FactoryRequest struct {
 	FormText     string `json:"form_text"`
 	GeminiAPIKey string `json:"gemini_api_key"` // MULTI KEY ; SEPARATED
 	Model        string `json:"model"`
+
+	// Alternatif form_text: dirender otomatis dari saves atau form_url
+	Saves        json.RawMessage `json:"saves,omitempty"`
+	FormURL      string          `json:"form_url,omitempty"`
+	FormTemplate string          `json:"form_template,omitempty"` // numbered | compact | markdown
+	Refresh      bool            `json:"refresh,omitempty"`       // scrape form_url tanpa cache
+	// Key dokumen jawaban untuk validasi hasil: text (default) | entry_id
+	SchemaKeyBy string `json:"schema_key_by,omitempty"`
+
+	// Stream: "ndjson" | "sse" -> event "task" per persona selesai lalu
+	// "summary" (progress-stream.go). Kosong = satu response JSON.
+	Stream string `json:"stream,omitempty"`
 }
 
 type FactoryResponse struct {
@@ -97,6 +112,25 @@ type FactoryResponse struct {
 	SuccessCount   int      `json:"success_count"`
 	Results        []string `json:"results"`
 	Errors         []string `json:"errors"`
+
+	// Validasi hasil terhadap JSON Schema form (hanya jika saves/form_url diisi)
+	Validation []ResultValidation `json:"validation,omitempty"`
+}
+
+// FactoryTaskEvent: event "task" saat streaming, satu per persona.
+type FactoryTaskEvent struct {
+	Task       int               `json:"task"`
+	Success    bool              `json:"success"`
+	Result     string            `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Validation *ResultValidation `json:"validation,omitempty"`
+}
+
+type ResultValidation struct {
+	Task  int             `json:"task"`
+	Valid bool            `json:"valid"`
+	Error string          `json:"error,omitempty"` // hasil bukan JSON dokumen jawaban
+	Rows  []RowViolations `json:"rows,omitempty"`
 }
 
 // ======================================================
@@ -160,6 +194,34 @@ func DataFactFactoryHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "prompt fields are incomplete", http.StatusBadRequest)
 		return
 	}
+	mode, ok := streamMode(req.Stream, r)
+	if !ok {
+		http.Error(w, "stream must be ndjson or sse", http.StatusBadRequest)
+		return
+	}
+
+	// saves / form_url -> render form_text jika kosong (form-renderer.go)
+	// dan JSON Schema untuk validasi hasil (form-schema.go)
+	var answerSchema *JSONSchema
+	if len(req.Saves) > 0 || req.FormURL != "" {
+		schema, err := loadFormSchema(req.Saves, req.FormURL, req.Refresh)
+		if err != nil {
+			writeFormSourceError(w, err)
+			return
+		}
+		if req.FormText == "" {
+			req.FormText, err = renderFormText(schema, req.FormTemplate)
+			if err != nil {
+				http.Error(w, "render form_text failed: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		answerSchema, err = buildAnswerSchema(schema, req.SchemaKeyBy)
+		if err != nil {
+			http.Error(w, "build answer schema failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	// 🔒 FORCE MODEL
 	req.Model = "gemini-2.5-flash"
@@ -173,35 +235,67 @@ func DataFactFactoryHandler(w http.ResponseWriter, r *http.Request) {
 	const maxConcurrency = 5
 	sem := make(chan struct{}, maxConcurrency)
 
+	// Semua call Gemini ikut context request: client putus = berhenti
+	ctx := r.Context()
+
 	n := len(req.SystemPromptFactory)
 	results := make([]string, n)
+	// Validasi per task sekali saja, dipakai event task & summary
+	validations := make([]*ResultValidation, n)
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	errorsList := []string{}
 	success := 0
 
+	var stream *progressStream
+	if mode != "" {
+		stream = newProgressStream(w, mode)
+	}
+
+dispatch:
 	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			// Task yang belum jalan tidak di-dispatch lagi
+			mu.Lock()
+			for j := i; j < n; j++ {
+				errorsList = append(errorsList, fmt.Sprintf("Task %d Fail: %v", j, ctx.Err()))
+			}
+			mu.Unlock()
+			break dispatch
+		}
+
 		wg.Add(1)
 		persona := req.SystemPromptFactory[i]
 
 		go func(idx int, personaPrompt string) {
 			defer wg.Done()
-			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			out, err := runFactoryThenParse(req, personaPrompt, keyPool)
+			out, err := runFactoryThenParse(ctx, req, personaPrompt, keyPool)
 			if err != nil {
 				mu.Lock()
 				errorsList = append(errorsList, fmt.Sprintf("Task %d Fail: %v", idx, err))
 				mu.Unlock()
+				if stream != nil {
+					stream.send("task", FactoryTaskEvent{Task: idx, Error: err.Error()})
+				}
 				return
 			}
 
 			results[idx] = out
+			if answerSchema != nil {
+				rv := validateFactoryResult(answerSchema, idx, out)
+				validations[idx] = &rv
+			}
 			mu.Lock()
 			success++
 			mu.Unlock()
+			if stream != nil {
+				stream.send("task", FactoryTaskEvent{Task: idx, Success: true, Result: out, Validation: validations[idx]})
+			}
 		}(i, persona)
 	}
 
@@ -214,28 +308,50 @@ func DataFactFactoryHandler(w http.ResponseWriter, r *http.Request) {
 		Errors:         errorsList,
 	}
 
+	for _, rv := range validations {
+		if rv != nil {
+			resp.Validation = append(resp.Validation, *rv)
+		}
+	}
+
+	if stream != nil {
+		stream.send("summary", resp)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
+// validateFactoryResult memeriksa hasil satu task terhadap JSON Schema form.
+func validateFactoryResult(schema *JSONSchema, idx int, out string) ResultValidation {
+	rv := ResultValidation{Task: idx}
+	if docs, err := decodeAnswerDocs([]byte(out)); err != nil {
+		rv.Error = err.Error()
+	} else {
+		rv.Rows = validateAnswerDocs(schema, docs)
+		rv.Valid = len(rv.Rows) == 0
+	}
+	return rv
+}
+
 // ======================================================
 // Pipeline Logic
 // ======================================================
 
-func runFactoryThenParse(req FactoryRequest, persona string, pool *GeminiKeyPool) (string, error) {
+func runFactoryThenParse(ctx context.Context, req FactoryRequest, persona string, pool *GeminiKeyPool) (string, error) {
 	userFactory := req.UserPromptFactory
 	if req.FormText != "" {
 		userFactory = strings.ReplaceAll(userFactory, "{{ $json.form }}", req.FormText)
 	}
 
-	gen, err := callGemini(req.Model, pool.Next(), persona, userFactory)
+	gen, err := callGemini(ctx, req.Model, pool.Next(), persona, userFactory)
 	if err != nil {
 		return "", err
 	}
 
 	parserInput := strings.TrimSpace(gen) + "\n\n" + strings.TrimSpace(req.UserPromptParser)
 
-	parsed, err := callGemini(req.Model, pool.Next(), req.SystemPromptParser, parserInput)
+	parsed, err := callGemini(ctx, req.Model, pool.Next(), req.SystemPromptParser, parserInput)
 	if err != nil {
 		return "", err
 	}
@@ -247,10 +363,10 @@ func runFactoryThenParse(req FactoryRequest, persona string, pool *GeminiKeyPool
 // Gemini Call (Retry + Timeout, NO FAIL)
 // ======================================================
 
-func callGemini(model, apiKey, systemPrompt, userPrompt string) (string, error) {
+func callGemini(ctx context.Context, model, apiKey, systemPrompt, userPrompt string) (string, error) {
 	url := fmt.Sprintf(
-		"https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
-		model, apiKey,
+		"%s/models/%s:generateContent?key=%s",
+		geminiBaseURL, model, apiKey,
 	)
 
 	payload := GeminiPayload{
@@ -276,15 +392,18 @@ func callGemini(model, apiKey, systemPrompt, userPrompt string) (string, error)
 	var lastErr error
 
 	for i := 0; i <= maxRetry; i++ {
-		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(body)))
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		callCtx, cancel := context.WithTimeout(ctx, 90*time.Second)
+		req, _ := http.NewRequestWithContext(callCtx, http.MethodPost, url, strings.NewReader(string(body)))
 		req.Header.Set("Content-Type", "application/json")
 
 		resp, err := geminiClient.Do(req)
 		if err != nil {
 			cancel()
 			lastErr = err
-			time.Sleep(time.Duration(i+1) * 2 * time.Second)
+			sleepCtx(ctx, time.Duration(i+1)*2*time.Second)
 			continue
 		}
 
@@ -299,7 +418,7 @@ func callGemini(model, apiKey, systemPrompt, userPrompt string) (string, error)
 
 		if resp.StatusCode != 200 {
 			lastErr = fmt.Errorf("gemini %d: %s", resp.StatusCode, data)
-			time.Sleep(time.Duration(i+1) * 2 * time.Second)
+			sleepCtx(ctx, time.Duration(i+1)*2*time.Second)
 			continue
 		}
 
@@ -316,3 +435,13 @@ func callGemini(model, apiKey, systemPrompt, userPrompt string) (string, error)
 
 	return "", lastErr
 }
+
+// sleepCtx: jeda retry yang berhenti lebih awal jika ctx selesai.
+func sleepCtx(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
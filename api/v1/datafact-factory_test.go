@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// startFakeGemini mengarahkan geminiBaseURL ke server yang selalu
+// menjawab text, lalu mengembalikan penghitung request.
+func startFakeGemini(t *testing.T, text string) *int32 {
+	t.Helper()
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		var resp GeminiResponse
+		resp.Candidates = make([]struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		}, 1)
+		resp.Candidates[0].Content.Parts = []struct {
+			Text string `json:"text"`
+		}{{Text: text}}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	prev := geminiBaseURL
+	geminiBaseURL = srv.URL
+	t.Cleanup(func() { geminiBaseURL = prev })
+	return &hits
+}
+
+func newFactoryRequest(t *testing.T, ctx context.Context, personas int) *http.Request {
+	t.Helper()
+	t.Setenv("DATAFACT_API_KEY", "test-key")
+	req := FactoryRequest{
+		UserPromptFactory:  "buat jawaban",
+		UserPromptParser:   "jadikan JSON",
+		SystemPromptParser: "parser",
+		GeminiAPIKey:       "k1;k2",
+		Saves: json.RawMessage(`{"form_id":"abc","fbzx":"1","entry_ids":[11],"entry_mappings":{"Nama":11},
+			"questions":[{"id":11,"text":"Nama","kind":"short_answer","required":true}]}`),
+	}
+	for i := 0; i < personas; i++ {
+		req.SystemPromptFactory = append(req.SystemPromptFactory, "persona")
+	}
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/factory", bytes.NewReader(body)).WithContext(ctx)
+	r.Header.Set("Authorization", "Bearer test-key")
+	return r
+}
+
+func TestFactoryValidatesEachResult(t *testing.T) {
+	hits := startFakeGemini(t, `[{"Nama":"Budi"}]`)
+	rec := httptest.NewRecorder()
+	DataFactFactoryHandler(rec, newFactoryRequest(t, context.Background(), 3))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp FactoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.SuccessCount != 3 || len(resp.Errors) != 0 {
+		t.Errorf("success = %d, errors = %v", resp.SuccessCount, resp.Errors)
+	}
+	// Dua call Gemini per persona: factory lalu parser
+	if *hits != 6 {
+		t.Errorf("gemini hits = %d, want 6", *hits)
+	}
+	if len(resp.Validation) != 3 {
+		t.Fatalf("validation = %+v, want one per task", resp.Validation)
+	}
+	for i, rv := range resp.Validation {
+		if rv.Task != i || !rv.Valid {
+			t.Errorf("validation[%d] = %+v", i, rv)
+		}
+	}
+}
+
+func TestFactoryStopsWhenClientGone(t *testing.T) {
+	hits := startFakeGemini(t, `[{"Nama":"Budi"}]`)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rec := httptest.NewRecorder()
+	DataFactFactoryHandler(rec, newFactoryRequest(t, ctx, 8))
+	var resp FactoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if *hits != 0 {
+		t.Errorf("gemini hits = %d after cancel, want 0", *hits)
+	}
+	if resp.SuccessCount != 0 || len(resp.Errors) != 8 {
+		t.Errorf("success = %d, errors = %d, want 0 / 8", resp.SuccessCount, len(resp.Errors))
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"container/list"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// =====================
+// Schema Cache
+// =====================
+
+// Cache in-process hasil scrape, key = form ID kanonik (FormRef.ID) agar
+// link viewform, prefilled dan forms.gle ke form yang sama berbagi entry.
+// Dipakai semua handler lewat scrapeGoogleForm.
+//
+// Konfigurasi via env:
+//   - DATAFACT_SCHEMA_CACHE_TTL: durasi Go (default 5m, 0 = cache mati)
+//   - DATAFACT_SCHEMA_CACHE_SIZE: jumlah form maksimal (default 256, LRU)
+var formSchemaCache = newSchemaCacheFromEnv()
+
+const (
+	CacheStatusHit         = "hit"         // dari cache, belum lewat TTL
+	CacheStatusMiss        = "miss"        // belum ada di cache, scrape penuh
+	CacheStatusRevalidated = "revalidated" // lewat TTL, upstream jawab 304
+	CacheStatusRefreshed   = "refreshed"   // refresh dipaksa caller
+)
+
+// CacheStatus: info cache yang ikut di ScrapeResponse.
+type CacheStatus struct {
+	Status     string    `json:"status"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	AgeSeconds int64     `json:"age_seconds"`
+}
+
+type schemaCacheEntry struct {
+	formID string
+	// data: hasil scrape dalam bentuk JSON. Tiap response di-decode ulang
+	// sehingga caller bebas mengubah Questions/Pages/Saves tanpa merusak
+	// entry cache (salinan dangkal berbagi slice & map yang sama).
+	data       []byte
+	validators formValidators
+	fetchedAt  time.Time
+}
+
+// response membuat salinan penuh data entry lalu menempelkan status cache.
+func (e schemaCacheEntry) response(status string, now time.Time) *ScrapeResponse {
+	out := &ScrapeResponse{}
+	json.Unmarshal(e.data, out)
+	out.Cache = &CacheStatus{
+		Status:     status,
+		FetchedAt:  e.fetchedAt,
+		AgeSeconds: int64(now.Sub(e.fetchedAt) / time.Second),
+	}
+	return out
+}
+
+type schemaCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element // value: *schemaCacheEntry
+	lru        *list.List               // depan = paling baru dipakai
+	now        func() time.Time
+}
+
+func newSchemaCache(ttl time.Duration, maxEntries int) *schemaCache {
+	return &schemaCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+func newSchemaCacheFromEnv() *schemaCache {
+	ttl, err := time.ParseDuration(getenv("DATAFACT_SCHEMA_CACHE_TTL", "5m"))
+	if err != nil {
+		ttl = 5 * time.Minute
+	}
+	size, err := strconv.Atoi(getenv("DATAFACT_SCHEMA_CACHE_SIZE", "256"))
+	if err != nil {
+		size = 256
+	}
+	return newSchemaCache(ttl, size)
+}
+
+func (c *schemaCache) enabled() bool {
+	return c.ttl > 0 && c.maxEntries > 0
+}
+
+// get mengembalikan salinan entry beserta status fresh (umur < TTL).
+// Entry yang sudah lewat TTL tetap dikembalikan untuk conditional GET.
+func (c *schemaCache) get(formID string) (schemaCacheEntry, bool, bool) {
+	if !c.enabled() {
+		return schemaCacheEntry{}, false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[formID]
+	if !ok {
+		return schemaCacheEntry{}, false, false
+	}
+	c.lru.MoveToFront(el)
+	entry := *el.Value.(*schemaCacheEntry)
+	return entry, c.now().Sub(entry.fetchedAt) < c.ttl, true
+}
+
+func (c *schemaCache) put(formID string, data *ScrapeResponse, validators formValidators) schemaCacheEntry {
+	raw, _ := json.Marshal(data)
+	entry := schemaCacheEntry{formID: formID, data: raw, validators: validators, fetchedAt: c.now()}
+	if !c.enabled() {
+		return entry
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[formID]; ok {
+		el.Value = &entry
+		c.lru.MoveToFront(el)
+		return entry
+	}
+	c.entries[formID] = c.lru.PushFront(&entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*schemaCacheEntry).formID)
+	}
+	return entry
+}
+
+// touch memperbarui fetchedAt setelah upstream menjawab 304.
+func (c *schemaCache) touch(formID string) (schemaCacheEntry, bool) {
+	if !c.enabled() {
+		return schemaCacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[formID]
+	if !ok {
+		return schemaCacheEntry{}, false
+	}
+	entry := el.Value.(*schemaCacheEntry)
+	entry.fetchedAt = c.now()
+	c.lru.MoveToFront(el)
+	return *entry, true
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"datafact/internal/fakeform"
+)
+
+func TestCachedScrapeResponsesAreIndependent(t *testing.T) {
+	data := `[null,[null,[[1,"Kota",null,2,[[11,[["Jakarta"],["Bandung"]],1]]]]],null,null,null,null,null,null,"Form"]`
+	startFakeForms(t, fakeform.Form{ID: "cached", LoadData: json.RawMessage(data), Fbzx: "1"})
+	link := googleFormsBase + "/d/e/cached/viewform"
+
+	first, err := scrapeGoogleForm(link, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Questions[0].Options[0] = "Diubah"
+	first.Saves.Questions[0].Text = "Diubah"
+	first.Pages[0].EntryIDs[0] = 0
+
+	second, err := scrapeGoogleForm(link, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Cache == nil || second.Cache.Status != CacheStatusHit {
+		t.Fatalf("second scrape cache = %+v, want hit", second.Cache)
+	}
+	if second.Questions[0].Options[0] != "Jakarta" || second.Saves.Questions[0].Text != "Kota" || second.Pages[0].EntryIDs[0] != 11 {
+		t.Errorf("cache entry changed by caller: %+v", second.Questions[0])
+	}
+}
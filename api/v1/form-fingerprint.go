@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+)
+
+// =====================
+// Schema Fingerprint
+// =====================
+
+// schemaShape: bagian struktural pertanyaan yang masuk ke hash. Perubahan
+// yang tidak mempengaruhi submission (misal deskripsi halaman) diabaikan.
+type schemaShape struct {
+	ID         int64            `json:"id"`
+	Text       string           `json:"text"`
+	Kind       QuestionKind     `json:"kind"`
+	Options    []string         `json:"options"`
+	HasOther   bool             `json:"has_other"`
+	Required   bool             `json:"required"`
+	Validation []ValidationRule `json:"validation"`
+	Rows       []GridRow        `json:"rows"`
+	GoTo       map[string]int64 `json:"go_to"`
+}
+
+func shapeOf(q QuestionItem) schemaShape {
+	return schemaShape{
+		ID:         q.ID,
+		Text:       q.Text,
+		Kind:       q.Kind,
+		Options:    q.Options,
+		HasOther:   q.HasOther,
+		Required:   q.Required,
+		Validation: q.Validation,
+		Rows:       q.Rows,
+		GoTo:       q.GoTo,
+	}
+}
+
+// computeSchemaHash menghasilkan hash SHA-256 yang stabil dari struktur
+// pertanyaan + urutan halaman. Form yang sama selalu menghasilkan hash
+// yang sama; hash berubah jika pemilik form mengedit pertanyaan.
+func computeSchemaHash(questions []QuestionItem, pages []FormPage) string {
+	type pageShape struct {
+		ID       int64   `json:"id"`
+		EntryIDs []int64 `json:"entry_ids"`
+		GoTo     int64   `json:"go_to"`
+	}
+	doc := struct {
+		Questions []schemaShape `json:"questions"`
+		Pages     []pageShape   `json:"pages"`
+	}{}
+	for _, q := range questions {
+		doc.Questions = append(doc.Questions, shapeOf(q))
+	}
+	for _, p := range pages {
+		doc.Pages = append(doc.Pages, pageShape{ID: p.ID, EntryIDs: p.EntryIDs, GoTo: p.GoTo})
+	}
+
+	// encoding/json mengurutkan key map, jadi hasil marshal deterministik
+	raw, _ := json.Marshal(doc)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// =====================
+// Schema Diff
+// =====================
+
+type SchemaChange struct {
+	EntryID int64    `json:"entry_id"`
+	Text    string   `json:"text"`
+	Fields  []string `json:"fields,omitempty"` // field yang berubah (hanya untuk changed)
+}
+
+// PageChange: perubahan satu halaman (section). Halaman pertama ID 0.
+type PageChange struct {
+	PageID int64    `json:"page_id"`
+	Index  int      `json:"index"`
+	Title  string   `json:"title"`
+	Change string   `json:"change"`           // added / removed / changed
+	Fields []string `json:"fields,omitempty"` // index, entry_ids, go_to (hanya untuk changed)
+}
+
+type SchemaDiff struct {
+	Added   []SchemaChange `json:"added"`
+	Removed []SchemaChange `json:"removed"`
+	Changed []SchemaChange `json:"changed"`
+	// Pages: halaman yang ditambah/dihapus/diubah (urutan, isi pertanyaan,
+	// navigasi). Ikut dicek karena halaman masuk ke computeSchemaHash.
+	Pages []PageChange `json:"pages"`
+}
+
+// diffSchemas membandingkan pertanyaan tersimpan dengan form live
+// (dicocokkan berdasarkan entry ID), lalu halaman (berdasarkan page ID).
+func diffSchemas(saved, live []QuestionItem, savedPages, livePages []FormPage) SchemaDiff {
+	diff := SchemaDiff{Added: []SchemaChange{}, Removed: []SchemaChange{}, Changed: []SchemaChange{}}
+	diff.Pages = diffPages(savedPages, livePages)
+
+	liveByID := make(map[int64]QuestionItem, len(live))
+	for _, q := range live {
+		liveByID[q.ID] = q
+	}
+	savedByID := make(map[int64]bool, len(saved))
+
+	for _, old := range saved {
+		savedByID[old.ID] = true
+		cur, ok := liveByID[old.ID]
+		if !ok {
+			diff.Removed = append(diff.Removed, SchemaChange{EntryID: old.ID, Text: old.Text})
+			continue
+		}
+		if fields := changedFields(shapeOf(old), shapeOf(cur)); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, SchemaChange{EntryID: cur.ID, Text: cur.Text, Fields: fields})
+		}
+	}
+	for _, q := range live {
+		if !savedByID[q.ID] {
+			diff.Added = append(diff.Added, SchemaChange{EntryID: q.ID, Text: q.Text})
+		}
+	}
+	return diff
+}
+
+// diffPages membandingkan bagian halaman yang masuk ke hash: posisi,
+// entry ID di dalamnya dan GoTo.
+func diffPages(saved, live []FormPage) []PageChange {
+	changes := []PageChange{}
+	liveByID := make(map[int64]int, len(live))
+	for i, p := range live {
+		liveByID[p.ID] = i
+	}
+	savedByID := make(map[int64]bool, len(saved))
+
+	for i, old := range saved {
+		savedByID[old.ID] = true
+		j, ok := liveByID[old.ID]
+		if !ok {
+			changes = append(changes, PageChange{PageID: old.ID, Index: i, Title: old.Title, Change: "removed"})
+			continue
+		}
+		cur := live[j]
+		var fields []string
+		if i != j {
+			fields = append(fields, "index")
+		}
+		if !reflect.DeepEqual(old.EntryIDs, cur.EntryIDs) {
+			fields = append(fields, "entry_ids")
+		}
+		if old.GoTo != cur.GoTo {
+			fields = append(fields, "go_to")
+		}
+		if len(fields) > 0 {
+			changes = append(changes, PageChange{PageID: cur.ID, Index: j, Title: cur.Title, Change: "changed", Fields: fields})
+		}
+	}
+	for j, p := range live {
+		if !savedByID[p.ID] {
+			changes = append(changes, PageChange{PageID: p.ID, Index: j, Title: p.Title, Change: "added"})
+		}
+	}
+	return changes
+}
+
+// changedFields membandingkan field per field berdasarkan tag JSON.
+func changedFields(a, b schemaShape) []string {
+	var fields []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, t.Field(i).Tag.Get("json"))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSchemasReportsPageChanges(t *testing.T) {
+	questions := []QuestionItem{
+		{ID: 11, Text: "Nama", Kind: KindShortAnswer},
+		{ID: 12, Text: "Kota", Kind: KindShortAnswer},
+	}
+	saved := []FormPage{
+		{Index: 0, ID: 0, Title: "Data diri", EntryIDs: []int64{11, 12}},
+		{Index: 1, ID: 500, Title: "Penutup"},
+	}
+	// Pertanyaan dipindah ke section lain dan navigasi diubah, isi
+	// pertanyaan tetap sama
+	live := []FormPage{
+		{Index: 0, ID: 0, Title: "Data diri", EntryIDs: []int64{11}},
+		{Index: 1, ID: 500, Title: "Penutup", EntryIDs: []int64{12}, GoTo: -3},
+		{Index: 2, ID: 600, Title: "Baru"},
+	}
+
+	if computeSchemaHash(questions, saved) == computeSchemaHash(questions, live) {
+		t.Fatal("hash did not change after moving a question between pages")
+	}
+	diff := diffSchemas(questions, questions, saved, live)
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed) != 0 {
+		t.Errorf("unexpected question changes: %+v", diff)
+	}
+
+	want := []PageChange{
+		{PageID: 0, Index: 0, Title: "Data diri", Change: "changed", Fields: []string{"entry_ids"}},
+		{PageID: 500, Index: 1, Title: "Penutup", Change: "changed", Fields: []string{"entry_ids", "go_to"}},
+		{PageID: 600, Index: 2, Title: "Baru", Change: "added"},
+	}
+	if !reflect.DeepEqual(diff.Pages, want) {
+		t.Errorf("pages diff = %+v, want %+v", diff.Pages, want)
+	}
+
+	if got := diffSchemas(questions, questions, saved, saved).Pages; len(got) != 0 {
+		t.Errorf("identical pages reported as changed: %+v", got)
+	}
+}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- Qualtrics QSF Import ---
+
+// File .qsf: {SurveyEntry, SurveyElements: [...]}. Elemen yang dipakai:
+//   - "SQ": satu pertanyaan (Payload = qsfQuestion)
+//   - "BL": daftar block; tiap block tampil di halaman sendiri
+//   - "FL": urutan block (survey flow)
+type qsfDoc struct {
+	SurveyEntry struct {
+		SurveyName        string `json:"SurveyName"`
+		SurveyDescription string `json:"SurveyDescription"`
+	} `json:"SurveyEntry"`
+	SurveyElements []qsfElement `json:"SurveyElements"`
+}
+
+type qsfElement struct {
+	Element string          `json:"Element"`
+	Payload json.RawMessage `json:"Payload"`
+}
+
+type qsfQuestion struct {
+	QuestionID          string                 `json:"QuestionID"`
+	QuestionText        string                 `json:"QuestionText"`
+	QuestionDescription string                 `json:"QuestionDescription"`
+	DataExportTag       string                 `json:"DataExportTag"`
+	QuestionType        string                 `json:"QuestionType"`
+	Selector            string                 `json:"Selector"`
+	SubSelector         string                 `json:"SubSelector"`
+	Choices             map[string]qsfChoice   `json:"-"`
+	ChoiceOrder         []interface{}          `json:"ChoiceOrder"`
+	Answers             map[string]qsfChoice   `json:"-"`
+	AnswerOrder         []interface{}          `json:"AnswerOrder"`
+	RawChoices          json.RawMessage        `json:"Choices"`
+	RawAnswers          json.RawMessage        `json:"Answers"`
+	Configuration       map[string]interface{} `json:"Configuration"`
+	Validation          struct {
+		Settings map[string]interface{} `json:"Settings"`
+	} `json:"Validation"`
+}
+
+type qsfChoice struct {
+	Display   string      `json:"Display"`
+	TextEntry interface{} `json:"TextEntry"`
+}
+
+type qsfBlock struct {
+	Type          string `json:"Type"`
+	Description   string `json:"Description"`
+	ID            string `json:"ID"`
+	BlockElements []struct {
+		Type       string `json:"Type"`
+		QuestionID string `json:"QuestionID"`
+	} `json:"BlockElements"`
+}
+
+type qsfFlowItem struct {
+	Type string        `json:"Type"`
+	ID   string        `json:"ID"`
+	Flow []qsfFlowItem `json:"Flow"`
+}
+
+// qsfChoiceMap: Choices/Answers di QSF bisa berupa object {"1": {...}}
+// atau array (export lama).
+func qsfChoiceMap(raw json.RawMessage) map[string]qsfChoice {
+	out := map[string]qsfChoice{}
+	if len(raw) == 0 {
+		return out
+	}
+	if err := json.Unmarshal(raw, &out); err == nil {
+		return out
+	}
+	var list []qsfChoice
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for i, c := range list {
+			out[strconv.Itoa(i+1)] = c
+		}
+	}
+	return out
+}
+
+// qsfOrdered mengembalikan label sesuai urutan order; jika order kosong,
+// key diurutkan secara numerik. Label dengan TextEntry menandai "Other".
+func qsfOrdered(choices map[string]qsfChoice, order []interface{}) (labels []string, hasOther bool) {
+	keys := make([]string, 0, len(choices))
+	for _, o := range order {
+		keys = append(keys, fmt.Sprint(o))
+	}
+	if len(keys) == 0 {
+		for k := range choices {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, errA := strconv.Atoi(keys[i])
+			b, errB := strconv.Atoi(keys[j])
+			if errA != nil || errB != nil {
+				return keys[i] < keys[j]
+			}
+			return a < b
+		})
+	}
+	for _, k := range keys {
+		c, ok := choices[k]
+		if !ok {
+			continue
+		}
+		if te, _ := toBool(c.TextEntry); te {
+			hasOther = true
+			continue
+		}
+		if label := stripHTML(c.Display); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels, hasOther
+}
+
+func importQualtrics(content []byte) (*ImportResponse, error) {
+	var doc qsfDoc
+	if err := parseFlexibleJSON(content, &doc); err != nil {
+		return nil, fmt.Errorf("QSF tidak valid: %v", err)
+	}
+	if len(doc.SurveyElements) == 0 {
+		return nil, fmt.Errorf("QSF tidak berisi SurveyElements")
+	}
+
+	questions := map[string]qsfQuestion{}
+	var questionOrder []string
+	var blocks []qsfBlock
+	var flowIDs []string
+
+	for _, el := range doc.SurveyElements {
+		switch el.Element {
+		case "SQ":
+			var q qsfQuestion
+			if err := json.Unmarshal(el.Payload, &q); err != nil {
+				continue
+			}
+			q.Choices = qsfChoiceMap(q.RawChoices)
+			q.Answers = qsfChoiceMap(q.RawAnswers)
+			questions[q.QuestionID] = q
+			questionOrder = append(questionOrder, q.QuestionID)
+		case "BL":
+			// Payload BL berupa array atau object {"0": {...}}
+			var list []qsfBlock
+			if err := json.Unmarshal(el.Payload, &list); err != nil {
+				var byKey map[string]qsfBlock
+				if err := json.Unmarshal(el.Payload, &byKey); err == nil {
+					keys := make([]string, 0, len(byKey))
+					for k := range byKey {
+						keys = append(keys, k)
+					}
+					sort.Slice(keys, func(i, j int) bool {
+						a, _ := strconv.Atoi(keys[i])
+						b, _ := strconv.Atoi(keys[j])
+						return a < b
+					})
+					for _, k := range keys {
+						list = append(list, byKey[k])
+					}
+				}
+			}
+			blocks = append(blocks, list...)
+		case "FL":
+			var flow qsfFlowItem
+			if err := json.Unmarshal(el.Payload, &flow); err == nil {
+				flowIDs = qsfFlowBlockIDs(flow.Flow)
+			}
+		}
+	}
+
+	b := newImportBuilder(ImportSourceQualtrics, doc.SurveyEntry.SurveyName, doc.SurveyEntry.SurveyDescription)
+	used := map[string]bool{}
+	// Pertanyaan di block Trash sudah dihapus pemilik survey
+	for _, blk := range blocks {
+		if blk.Type == "Trash" {
+			for _, be := range blk.BlockElements {
+				used[be.QuestionID] = true
+			}
+		}
+	}
+
+	ordered, unflowed := qsfOrderBlocks(blocks, flowIDs)
+	for _, blk := range unflowed {
+		b.warn("block %s %q tidak ada di survey flow, ditaruh di akhir", blk.ID, blk.Description)
+	}
+	for _, blk := range append(ordered, unflowed...) {
+		b.addPage(blk.Description, "")
+		for _, be := range blk.BlockElements {
+			switch be.Type {
+			case "Page Break":
+				b.addPage(blk.Description, "")
+			case "Question":
+				if q, ok := questions[be.QuestionID]; ok && !used[be.QuestionID] {
+					used[be.QuestionID] = true
+					addQualtricsQuestion(b, q)
+				}
+			}
+		}
+	}
+	// Tanpa block (atau pertanyaan di luar block): urutan di file
+	var outside []string
+	for _, id := range questionOrder {
+		if !used[id] {
+			outside = append(outside, id)
+			addQualtricsQuestion(b, questions[id])
+		}
+	}
+	if len(blocks) > 0 && len(outside) > 0 {
+		b.warn("%d pertanyaan di luar block (%s) ditaruh di akhir", len(outside), strings.Join(outside, ", "))
+	}
+	return b.result()
+}
+
+func qsfFlowBlockIDs(flow []qsfFlowItem) []string {
+	var ids []string
+	for _, f := range flow {
+		if f.ID != "" && (f.Type == "Block" || f.Type == "Standard" || f.Type == "Default") {
+			ids = append(ids, f.ID)
+		}
+		ids = append(ids, qsfFlowBlockIDs(f.Flow)...)
+	}
+	return ids
+}
+
+// qsfOrderBlocks: block sesuai survey flow (jika ada), tanpa Trash.
+// Block yang tidak disebut di flow dikembalikan terpisah (urutan file).
+func qsfOrderBlocks(blocks []qsfBlock, flowIDs []string) (ordered, unflowed []qsfBlock) {
+	byID := map[string]qsfBlock{}
+	for _, blk := range blocks {
+		if blk.Type == "Trash" {
+			continue
+		}
+		byID[blk.ID] = blk
+		if len(flowIDs) == 0 {
+			ordered = append(ordered, blk)
+		}
+	}
+	if len(flowIDs) == 0 {
+		return ordered, nil
+	}
+	for _, id := range flowIDs {
+		if blk, ok := byID[id]; ok {
+			ordered = append(ordered, blk)
+			delete(byID, id)
+		}
+	}
+	for _, blk := range blocks {
+		if _, ok := byID[blk.ID]; ok {
+			unflowed = append(unflowed, blk)
+			delete(byID, blk.ID)
+		}
+	}
+	return ordered, unflowed
+}
+
+func addQualtricsQuestion(b *importBuilder, src qsfQuestion) {
+	q := QuestionItem{Text: stripHTML(src.QuestionText)}
+	if q.Text == "" {
+		q.Text = src.QuestionDescription
+	}
+	if q.Text == "" {
+		q.Text = src.DataExportTag
+	}
+	settings := src.Validation.Settings
+	// ForceResponse "ON" = wajib; "RequestResponse" hanya mengingatkan
+	if force, _ := settings["ForceResponse"].(string); force == "ON" {
+		q.Required = true
+	}
+
+	switch src.QuestionType {
+	case "MC":
+		q.Options, q.HasOther = qsfOrdered(src.Choices, src.ChoiceOrder)
+		switch src.Selector {
+		case "MAVR", "MAHR", "MACOL", "MSB":
+			q.Kind = KindCheckbox
+		case "DL", "SB":
+			q.Kind = KindDropdown
+		case "NPS":
+			q.Kind = KindLinearScale
+			q.Options, q.HasOther = nil, false
+			q.Scale = &ScaleMeta{Min: 0, Max: 10}
+		default:
+			q.Kind = KindMultipleChoice
+		}
+	case "TE":
+		switch src.Selector {
+		case "ML", "ESTB":
+			q.Kind = KindParagraph
+		case "FORM":
+			// Form teks: satu isian per choice, dipecah jadi pertanyaan sendiri
+			labels, _ := qsfOrdered(src.Choices, src.ChoiceOrder)
+			for _, label := range labels {
+				b.addQuestion(QuestionItem{Text: q.Text + " - " + label, Kind: KindShortAnswer, Required: q.Required})
+			}
+			return
+		default:
+			q.Kind = KindShortAnswer
+		}
+		q.Validation = qsfTextValidation(settings)
+	case "Matrix":
+		rows, _ := qsfOrdered(src.Choices, src.ChoiceOrder)
+		q.Options, _ = qsfOrdered(src.Answers, src.AnswerOrder)
+		q.Kind = KindGrid
+		if src.SubSelector == "MultipleAnswer" {
+			q.Kind = KindCheckboxGrid
+		}
+		for _, label := range rows {
+			q.Rows = append(q.Rows, GridRow{Label: label})
+		}
+		if len(q.Rows) == 0 || src.Selector == "TE" || src.Selector == "CS" {
+			b.warn("%s %q: matrix %s tidak didukung, dilewati", src.QuestionID, q.Text, src.Selector)
+			return
+		}
+	case "Slider":
+		// Slider -> jawaban angka dengan batas min/max
+		q.Kind = KindShortAnswer
+		lo, hasLo := src.Configuration["CSSliderMin"]
+		hi, hasHi := src.Configuration["CSSliderMax"]
+		if hasLo && hasHi {
+			q.Validation = []ValidationRule{{Type: "number", Operator: "between", Args: []string{fmt.Sprint(lo), fmt.Sprint(hi)}}}
+		}
+	case "FileUpload":
+		q.Kind = KindFileUpload
+	case "DB", "Timing", "Meta", "Captcha":
+		// Teks deskriptif / metadata, bukan pertanyaan
+		return
+	default:
+		b.warn("%s %q: tipe Qualtrics %s/%s tidak didukung, dilewati", src.QuestionID, q.Text, src.QuestionType, src.Selector)
+		return
+	}
+
+	if q.Kind == KindCheckbox {
+		q.Validation = append(q.Validation, qsfChoiceCountValidation(settings)...)
+	}
+	b.addQuestion(q)
+}
+
+// qsfTextValidation: ContentType (email/angka) & batas karakter.
+func qsfTextValidation(settings map[string]interface{}) []ValidationRule {
+	var rules []ValidationRule
+	str := func(key string) string { s, _ := settings[key].(string); return s }
+
+	switch str("Type") {
+	case "ContentType":
+		switch str("ContentType") {
+		case "ValidEmail":
+			rules = append(rules, ValidationRule{Type: "text", Operator: "email"})
+		case "ValidNumber":
+			rule := ValidationRule{Type: "number", Operator: "is_number"}
+			if num, ok := settings["ValidNumber"].(map[string]interface{}); ok {
+				lo, _ := num["Min"].(string)
+				hi, _ := num["Max"].(string)
+				switch {
+				case lo != "" && hi != "":
+					rule.Operator, rule.Args = "between", []string{lo, hi}
+				case lo != "":
+					rule.Operator, rule.Args = "gte", []string{lo}
+				case hi != "":
+					rule.Operator, rule.Args = "lte", []string{hi}
+				}
+			}
+			rules = append(rules, rule)
+		case "ValidURL":
+			rules = append(rules, ValidationRule{Type: "text", Operator: "url"})
+		}
+	case "MinChar":
+		if n := str("MinChars"); n != "" {
+			rules = append(rules, ValidationRule{Type: "length", Operator: "min_length", Args: []string{n}})
+		}
+	case "TotalChar", "MaxChar":
+		if n := str("TotalChars"); n != "" {
+			rules = append(rules, ValidationRule{Type: "length", Operator: "max_length", Args: []string{n}})
+		}
+	}
+	return rules
+}
+
+// qsfChoiceCountValidation: batas jumlah pilihan checkbox (MinChoices/MaxChoices).
+func qsfChoiceCountValidation(settings map[string]interface{}) []ValidationRule {
+	var rules []ValidationRule
+	if t, _ := settings["Type"].(string); t != "MinChoices" && t != "MaxChoices" && t != "RangeChoices" && t != "ExactChoices" {
+		return nil
+	}
+	if n, ok := settings["MinChoices"].(string); ok && n != "" {
+		rules = append(rules, ValidationRule{Type: "checkbox", Operator: "at_least", Args: []string{n}})
+	}
+	if n, ok := settings["MaxChoices"].(string); ok && n != "" {
+		rules = append(rules, ValidationRule{Type: "checkbox", Operator: "at_most", Args: []string{n}})
+	}
+	return rules
+}
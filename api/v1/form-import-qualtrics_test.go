@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const qsfFixture = `{
+  "SurveyEntry": {"SurveyName": "Survei QSF", "SurveyDescription": "Contoh"},
+  "SurveyElements": [
+    {"Element": "BL", "Payload": [
+      {"Type": "Default", "Description": "Blok 1", "ID": "BL_1", "BlockElements": [
+        {"Type": "Question", "QuestionID": "QID1"},
+        {"Type": "Page Break"},
+        {"Type": "Question", "QuestionID": "QID2"}
+      ]},
+      {"Type": "Standard", "Description": "Blok 2", "ID": "BL_2", "BlockElements": [
+        {"Type": "Question", "QuestionID": "QID3"}
+      ]},
+      {"Type": "Standard", "Description": "Di luar flow", "ID": "BL_3", "BlockElements": [
+        {"Type": "Question", "QuestionID": "QID4"}
+      ]},
+      {"Type": "Trash", "Description": "Trash", "ID": "BL_T", "BlockElements": [
+        {"Type": "Question", "QuestionID": "QID9"}
+      ]}
+    ]},
+    {"Element": "FL", "Payload": {"Type": "Root", "Flow": [
+      {"Type": "Block", "ID": "BL_2"},
+      {"Type": "Standard", "ID": "BL_1"}
+    ]}},
+    {"Element": "SQ", "Payload": {"QuestionID": "QID1", "QuestionText": "<b>Nama</b>", "QuestionType": "TE", "Selector": "SL",
+      "Validation": {"Settings": {"ForceResponse": "ON", "Type": "ContentType", "ContentType": "ValidEmail"}}}},
+    {"Element": "SQ", "Payload": {"QuestionID": "QID2", "QuestionText": "Hobi", "QuestionType": "MC", "Selector": "MAVR",
+      "Choices": {"1": {"Display": "Membaca"}, "2": {"Display": "Musik"}, "3": {"Display": "Lainnya", "TextEntry": "true"}},
+      "ChoiceOrder": [2, 1, 3],
+      "Validation": {"Settings": {"Type": "MaxChoices", "MaxChoices": "2"}}}},
+    {"Element": "SQ", "Payload": {"QuestionID": "QID3", "QuestionText": "Rekomendasi", "QuestionType": "MC", "Selector": "NPS"}},
+    {"Element": "SQ", "Payload": {"QuestionID": "QID4", "QuestionText": "Kota", "QuestionType": "MC", "Selector": "DL",
+      "Choices": [{"Display": "Jakarta"}, {"Display": "Bandung"}]}},
+    {"Element": "SQ", "Payload": {"QuestionID": "QID5", "QuestionText": "Saran", "QuestionType": "TE", "Selector": "ML"}},
+    {"Element": "SQ", "Payload": {"QuestionID": "QID9", "QuestionText": "Dihapus", "QuestionType": "TE", "Selector": "SL"}}
+  ]
+}`
+
+func TestImportQualtrics(t *testing.T) {
+	res, err := importQualtrics([]byte(qsfFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	for _, q := range res.Questions {
+		texts = append(texts, q.Text)
+	}
+	// Urutan flow, lalu block di luar flow, lalu pertanyaan di luar block
+	want := []string{"Rekomendasi", "Nama", "Hobi", "Kota", "Saran"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Fatalf("questions = %v, want %v", texts, want)
+	}
+
+	byText := map[string]QuestionItem{}
+	for _, q := range res.Questions {
+		byText[q.Text] = q
+	}
+	if q := byText["Nama"]; q.Kind != KindShortAnswer || !q.Required || len(q.Validation) != 1 || q.Validation[0].Operator != "email" {
+		t.Errorf("Nama = %+v", q)
+	}
+	if q := byText["Hobi"]; q.Kind != KindCheckbox || !q.HasOther || !reflect.DeepEqual(q.Options, []string{"Musik", "Membaca"}) {
+		t.Errorf("Hobi = %+v", q)
+	}
+	if q := byText["Rekomendasi"]; q.Kind != KindLinearScale || q.Scale == nil || q.Scale.Min != 0 || q.Scale.Max != 10 {
+		t.Errorf("Rekomendasi = %+v", q)
+	}
+	if q := byText["Kota"]; q.Kind != KindDropdown || !reflect.DeepEqual(q.Options, []string{"Jakarta", "Bandung"}) {
+		t.Errorf("Kota = %+v", q)
+	}
+
+	// Blok 2, Blok 1, page break di Blok 1, Di luar flow
+	if len(res.Pages) != 4 {
+		t.Errorf("got %d pages, want 4: %+v", len(res.Pages), res.Pages)
+	}
+	warnings := strings.Join(res.Warnings, "\n")
+	for _, want := range []string{"BL_3", "QID5"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings %q do not mention %s", res.Warnings, want)
+		}
+	}
+}
+
+func TestImportQualtricsQuestionOutsideBlock(t *testing.T) {
+	qsf := `{"SurveyElements": [
+	  {"Element": "BL", "Payload": [{"Type": "Default", "ID": "BL_1", "BlockElements": [{"Type": "Question", "QuestionID": "QID1"}]}]},
+	  {"Element": "SQ", "Payload": {"QuestionID": "QID1", "QuestionText": "Di block", "QuestionType": "TE", "Selector": "SL"}},
+	  {"Element": "SQ", "Payload": {"QuestionID": "QID2", "QuestionText": "Di luar", "QuestionType": "TE", "Selector": "SL"}}
+	]}`
+	res, err := importQualtrics([]byte(qsf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Questions) != 2 || res.Questions[1].Text != "Di luar" {
+		t.Errorf("questions = %+v, want both", res.Questions)
+	}
+	if len(res.Warnings) != 1 {
+		t.Errorf("warnings = %q, want one for QID2", res.Warnings)
+	}
+}
+
+func TestImportQualtricsErrors(t *testing.T) {
+	cases := map[string]string{
+		"not json":     "SurveyElements",
+		"no elements":  `{"SurveyEntry": {"SurveyName": "x"}}`,
+		"no questions": `{"SurveyElements": [{"Element": "SQ", "Payload": {"QuestionID": "QID1", "QuestionType": "DB"}}]}`,
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := importQualtrics([]byte(src)); err == nil {
+				t.Errorf("expected error for %s", src)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- SurveyJS Import ---
+
+// Dokumen SurveyJS: {title, description, pages: [{title, elements}]} atau
+// elements/questions langsung di root. String boleh berupa object
+// terlokalisasi {"default": "...", "id": "..."}.
+type surveyJSDoc struct {
+	Title       interface{}       `json:"title"`
+	Description interface{}       `json:"description"`
+	Pages       []surveyJSPage    `json:"pages"`
+	Elements    []surveyJSElement `json:"elements"`
+	Questions   []surveyJSElement `json:"questions"`
+}
+
+type surveyJSPage struct {
+	Name        string            `json:"name"`
+	Title       interface{}       `json:"title"`
+	Description interface{}       `json:"description"`
+	Elements    []surveyJSElement `json:"elements"`
+	Questions   []surveyJSElement `json:"questions"`
+}
+
+type surveyJSElement struct {
+	Type        string      `json:"type"`
+	Name        string      `json:"name"`
+	Title       interface{} `json:"title"`
+	Description interface{} `json:"description"`
+	IsRequired  bool        `json:"isRequired"`
+	InputType   string      `json:"inputType"`
+
+	Choices            []interface{} `json:"choices"`
+	HasOther           bool          `json:"hasOther"`
+	ShowOtherItem      bool          `json:"showOtherItem"`
+	MinSelectedChoices int           `json:"minSelectedChoices"`
+	MaxSelectedChoices int           `json:"maxSelectedChoices"`
+	MaxLength          int           `json:"maxLength"`
+	Min                interface{}   `json:"min"`
+	Max                interface{}   `json:"max"`
+
+	// rating
+	RateMin            *int64              `json:"rateMin"`
+	RateMax            *int64              `json:"rateMax"`
+	RateValues         []interface{}       `json:"rateValues"`
+	MinRateDescription interface{}         `json:"minRateDescription"`
+	MaxRateDescription interface{}         `json:"maxRateDescription"`
+	LabelTrue          interface{}         `json:"labelTrue"`
+	LabelFalse         interface{}         `json:"labelFalse"`
+	Columns            []interface{}       `json:"columns"`
+	Rows               []interface{}       `json:"rows"`
+	ImageLink          string              `json:"imageLink"`
+	Validators         []surveyJSValidator `json:"validators"`
+	Elements           []surveyJSElement   `json:"elements"` // panel
+	Questions          []surveyJSElement   `json:"questions"`
+}
+
+type surveyJSValidator struct {
+	Type      string      `json:"type"`
+	Text      interface{} `json:"text"`
+	MinValue  interface{} `json:"minValue"`
+	MaxValue  interface{} `json:"maxValue"`
+	MinLength int         `json:"minLength"`
+	MaxLength int         `json:"maxLength"`
+	MinCount  int         `json:"minCount"`
+	MaxCount  int         `json:"maxCount"`
+	Regex     string      `json:"regex"`
+}
+
+// surveyJSText mengambil teks dari string biasa atau string terlokalisasi.
+func surveyJSText(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return stripHTML(t)
+	case map[string]interface{}:
+		if d, ok := t["default"]; ok {
+			return surveyJSText(d)
+		}
+		for _, alt := range t {
+			if s := surveyJSText(alt); s != "" {
+				return s
+			}
+		}
+	case float64, bool:
+		return fmt.Sprint(t)
+	}
+	return ""
+}
+
+// surveyJSItemLabels: choices/rows/columns berupa string, angka, atau
+// {value, text}. Label (text) yang dipakai karena Google juga menerima label.
+func surveyJSItemLabels(items []interface{}) []string {
+	var out []string
+	for _, it := range items {
+		label := ""
+		if m, ok := it.(map[string]interface{}); ok {
+			label = surveyJSText(m["text"])
+			if label == "" {
+				label = surveyJSText(m["value"])
+			}
+			if label == "" {
+				label = surveyJSText(m["name"])
+			}
+		} else {
+			label = surveyJSText(it)
+		}
+		if label != "" {
+			out = append(out, label)
+		}
+	}
+	return out
+}
+
+func importSurveyJS(content []byte) (*ImportResponse, error) {
+	var doc surveyJSDoc
+	if err := parseFlexibleJSON(content, &doc); err != nil {
+		return nil, fmt.Errorf("SurveyJS JSON tidak valid: %v", err)
+	}
+
+	b := newImportBuilder(ImportSourceSurveyJS, surveyJSText(doc.Title), surveyJSText(doc.Description))
+	pages := doc.Pages
+	if len(pages) == 0 {
+		pages = []surveyJSPage{{Elements: append(doc.Elements, doc.Questions...)}}
+	}
+	for i, p := range pages {
+		title := surveyJSText(p.Title)
+		if title == "" && i > 0 {
+			title = p.Name
+		}
+		b.addPage(title, surveyJSText(p.Description))
+		addSurveyJSElements(b, append(p.Elements, p.Questions...))
+	}
+	return b.result()
+}
+
+func addSurveyJSElements(b *importBuilder, elements []surveyJSElement) {
+	for _, el := range elements {
+		addSurveyJSElement(b, el)
+	}
+}
+
+func addSurveyJSElement(b *importBuilder, el surveyJSElement) {
+	q := QuestionItem{
+		Text:     surveyJSText(el.Title),
+		HelpText: surveyJSText(el.Description),
+		Required: el.IsRequired,
+	}
+	if q.Text == "" {
+		q.Text = el.Name
+	}
+
+	switch el.Type {
+	case "panel", "paneldynamic":
+		// Panel hanya pengelompokan, isinya diratakan ke halaman yang sama
+		addSurveyJSElements(b, append(el.Elements, el.Questions...))
+		return
+	case "html", "expression":
+		return
+	case "image":
+		b.addMedia(MediaItem{Kind: "image", Title: q.Text, URL: el.ImageLink})
+		return
+	case "text", "":
+		q.Kind = KindShortAnswer
+		switch el.InputType {
+		case "date":
+			q.Kind, q.Date = KindDate, &DateMeta{IncludeYear: true}
+		case "datetime", "datetime-local":
+			q.Kind, q.Date = KindDate, &DateMeta{IncludeYear: true, IncludeTime: true}
+		case "time":
+			q.Kind, q.Time = KindTime, &TimeMeta{}
+		case "number", "range":
+			q.Validation = append(q.Validation, surveyJSNumberRule(el.Min, el.Max, ""))
+		case "email":
+			q.Validation = append(q.Validation, ValidationRule{Type: "text", Operator: "email"})
+		case "url":
+			q.Validation = append(q.Validation, ValidationRule{Type: "text", Operator: "url"})
+		}
+	case "comment":
+		q.Kind = KindParagraph
+	case "radiogroup", "imagepicker":
+		q.Kind = KindMultipleChoice
+	case "dropdown":
+		q.Kind = KindDropdown
+	case "checkbox", "tagbox":
+		q.Kind = KindCheckbox
+	case "boolean":
+		q.Kind = KindMultipleChoice
+		q.Options = []string{surveyJSText(el.LabelTrue), surveyJSText(el.LabelFalse)}
+		if q.Options[0] == "" {
+			q.Options[0] = "Yes"
+		}
+		if q.Options[1] == "" {
+			q.Options[1] = "No"
+		}
+	case "rating":
+		q.Kind = KindLinearScale
+		if len(el.RateValues) > 0 {
+			q.Options = surveyJSItemLabels(el.RateValues)
+			q.Scale = &ScaleMeta{Min: 1, Max: int64(len(q.Options))}
+			if len(q.Options) > 0 {
+				if lo, err := strconv.ParseInt(q.Options[0], 10, 64); err == nil {
+					q.Scale.Min = lo
+				}
+				if hi, err := strconv.ParseInt(q.Options[len(q.Options)-1], 10, 64); err == nil {
+					q.Scale.Max = hi
+				}
+			}
+		} else {
+			q.Scale = &ScaleMeta{Min: 1, Max: 5}
+			if el.RateMin != nil {
+				q.Scale.Min = *el.RateMin
+			}
+			if el.RateMax != nil {
+				q.Scale.Max = *el.RateMax
+			}
+		}
+		q.Scale.MinLabel = surveyJSText(el.MinRateDescription)
+		q.Scale.MaxLabel = surveyJSText(el.MaxRateDescription)
+	case "matrix":
+		q.Kind = KindGrid
+		q.Options = surveyJSItemLabels(el.Columns)
+		for _, label := range surveyJSItemLabels(el.Rows) {
+			q.Rows = append(q.Rows, GridRow{Label: label})
+		}
+		if len(q.Rows) == 0 {
+			b.warn("%q: matrix tanpa baris dilewati", q.Text)
+			return
+		}
+	case "file":
+		q.Kind = KindFileUpload
+	default:
+		b.warn("%q: tipe SurveyJS %q tidak didukung, dilewati", q.Text, el.Type)
+		return
+	}
+
+	if q.Kind.IsChoice() && q.Kind != KindLinearScale && q.Options == nil {
+		q.Options = surveyJSItemLabels(el.Choices)
+		q.HasOther = el.HasOther || el.ShowOtherItem
+	}
+	if el.MaxLength > 0 {
+		q.Validation = append(q.Validation, ValidationRule{Type: "length", Operator: "max_length", Args: []string{strconv.Itoa(el.MaxLength)}})
+	}
+	if q.Kind == KindCheckbox {
+		if el.MinSelectedChoices > 0 {
+			q.Validation = append(q.Validation, ValidationRule{Type: "checkbox", Operator: "at_least", Args: []string{strconv.Itoa(el.MinSelectedChoices)}})
+		}
+		if el.MaxSelectedChoices > 0 {
+			q.Validation = append(q.Validation, ValidationRule{Type: "checkbox", Operator: "at_most", Args: []string{strconv.Itoa(el.MaxSelectedChoices)}})
+		}
+	}
+	for _, v := range el.Validators {
+		if rules := surveyJSValidatorRules(v); len(rules) > 0 {
+			q.Validation = append(q.Validation, rules...)
+		} else {
+			b.warn("%q: validator %q tidak didukung, diabaikan", q.Text, v.Type)
+		}
+	}
+
+	b.addQuestion(q)
+}
+
+// surveyJSNumberRule: batas min/max angka sebagai ValidationRule number.
+func surveyJSNumberRule(min, max interface{}, message string) ValidationRule {
+	lo, hasLo := surveyJSNumber(min)
+	hi, hasHi := surveyJSNumber(max)
+	rule := ValidationRule{Type: "number", Operator: "is_number", Message: message}
+	switch {
+	case hasLo && hasHi:
+		rule.Operator, rule.Args = "between", []string{lo, hi}
+	case hasLo:
+		rule.Operator, rule.Args = "gte", []string{lo}
+	case hasHi:
+		rule.Operator, rule.Args = "lte", []string{hi}
+	}
+	return rule
+}
+
+func surveyJSNumber(v interface{}) (string, bool) {
+	switch n := v.(type) {
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64), true
+	case string:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+			return strings.TrimSpace(n), true
+		}
+	}
+	return "", false
+}
+
+// surveyJSValidatorRules menerjemahkan validator SurveyJS. Validator yang
+// tidak punya padanan di Google Form menghasilkan slice kosong.
+func surveyJSValidatorRules(v surveyJSValidator) []ValidationRule {
+	msg := surveyJSText(v.Text)
+	rule := func(typ, op string, n int) ValidationRule {
+		return ValidationRule{Type: typ, Operator: op, Args: []string{strconv.Itoa(n)}, Message: msg}
+	}
+	var rules []ValidationRule
+	switch v.Type {
+	case "numeric":
+		rules = append(rules, surveyJSNumberRule(v.MinValue, v.MaxValue, msg))
+	case "email":
+		rules = append(rules, ValidationRule{Type: "text", Operator: "email", Message: msg})
+	case "regex":
+		if v.Regex != "" {
+			rules = append(rules, ValidationRule{Type: "regex", Operator: "matches", Args: []string{v.Regex}, Message: msg})
+		}
+	case "text":
+		if v.MinLength > 0 {
+			rules = append(rules, rule("length", "min_length", v.MinLength))
+		}
+		if v.MaxLength > 0 {
+			rules = append(rules, rule("length", "max_length", v.MaxLength))
+		}
+	case "answercount":
+		if v.MinCount > 0 {
+			rules = append(rules, rule("checkbox", "at_least", v.MinCount))
+		}
+		if v.MaxCount > 0 {
+			rules = append(rules, rule("checkbox", "at_most", v.MaxCount))
+		}
+	}
+	return rules
+}
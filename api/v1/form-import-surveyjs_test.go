@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+)
+
+const surveyJSFixture = `{
+  "title": {"default": "Survei SurveyJS", "id": "Survei"},
+  "pages": [
+    {"name": "p1", "elements": [
+      {"type": "text", "name": "nama", "title": "Nama", "isRequired": true, "maxLength": 50},
+      {"type": "text", "name": "usia", "title": "Usia", "inputType": "number", "min": 17, "max": 99},
+      {"type": "panel", "elements": [
+        {"type": "checkbox", "name": "hobi", "title": "Hobi", "choices": ["Membaca", {"value": "music", "text": "Musik"}],
+         "hasOther": true, "maxSelectedChoices": 2}
+      ]},
+      {"type": "html", "name": "info"}
+    ]},
+    {"name": "p2", "elements": [
+      {"type": "rating", "name": "puas", "title": "Kepuasan", "rateMin": 1, "rateMax": 10},
+      {"type": "boolean", "name": "setuju", "title": "Setuju?"},
+      {"type": "matrix", "name": "layanan", "title": "Layanan", "columns": ["Buruk", "Baik"], "rows": ["Kecepatan", "Keramahan"]},
+      {"type": "text", "name": "email", "title": "Email", "validators": [{"type": "email", "text": "Email tidak valid"}, {"type": "expression"}]},
+      {"type": "signaturepad", "name": "ttd", "title": "Tanda tangan"}
+    ]}
+  ]
+}`
+
+func TestImportSurveyJS(t *testing.T) {
+	res, err := importSurveyJS([]byte(surveyJSFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pages[0].Title != "Survei SurveyJS" || len(res.Pages) != 2 || res.Pages[1].Title != "p2" {
+		t.Errorf("pages = %+v", res.Pages)
+	}
+
+	byText := map[string]QuestionItem{}
+	var texts []string
+	for _, q := range res.Questions {
+		byText[q.Text] = q
+		texts = append(texts, q.Text)
+	}
+	want := []string{"Nama", "Usia", "Hobi", "Kepuasan", "Setuju?", "Layanan", "Email"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Fatalf("questions = %v, want %v", texts, want)
+	}
+
+	if q := byText["Nama"]; !q.Required || !reflect.DeepEqual(q.Validation, []ValidationRule{{Type: "length", Operator: "max_length", Args: []string{"50"}}}) {
+		t.Errorf("Nama = %+v", q)
+	}
+	if q := byText["Usia"]; !reflect.DeepEqual(q.Validation, []ValidationRule{{Type: "number", Operator: "between", Args: []string{"17", "99"}}}) {
+		t.Errorf("Usia validation = %+v", q.Validation)
+	}
+	if q := byText["Hobi"]; q.Kind != KindCheckbox || !q.HasOther || !reflect.DeepEqual(q.Options, []string{"Membaca", "Musik"}) {
+		t.Errorf("Hobi = %+v", q)
+	}
+	if q := byText["Kepuasan"]; q.Kind != KindLinearScale || q.Scale == nil || q.Scale.Max != 10 || len(q.Options) != 10 {
+		t.Errorf("Kepuasan = %+v", q)
+	}
+	if q := byText["Setuju?"]; !reflect.DeepEqual(q.Options, []string{"Yes", "No"}) {
+		t.Errorf("Setuju? options = %v", q.Options)
+	}
+	if q := byText["Layanan"]; q.Kind != KindGrid || len(q.Rows) != 2 || q.ID != q.Rows[0].ID {
+		t.Errorf("Layanan = %+v", q)
+	}
+	// Validator expression & tipe signaturepad tidak didukung
+	if len(res.Warnings) != 2 {
+		t.Errorf("warnings = %q, want 2", res.Warnings)
+	}
+}
+
+func TestImportSurveyJSRootElements(t *testing.T) {
+	res, err := importSurveyJS([]byte(`{"questions": [{"type": "comment", "name": "saran"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Questions) != 1 || res.Questions[0].Text != "saran" || res.Questions[0].Kind != KindParagraph {
+		t.Errorf("questions = %+v", res.Questions)
+	}
+}
+
+func TestImportSurveyJSErrors(t *testing.T) {
+	cases := map[string]string{
+		"not json":     "pages:",
+		"no questions": `{"pages": [{"elements": [{"type": "html"}]}]}`,
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := importSurveyJS([]byte(src)); err == nil {
+				t.Errorf("expected error for %s", src)
+			}
+		})
+	}
+}
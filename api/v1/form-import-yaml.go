@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- YAML Questionnaire Import ---
+
+// Format YAML kuesioner (JSON dengan struktur sama juga diterima):
+//
+//	title: Survei Belanja
+//	description: Pilot study
+//	questions:
+//	  - text: Nama
+//	    type: short_answer        # QuestionKind, atau alias: text, radio, scale, matrix, ...
+//	    required: true
+//	  - text: Platform favorit
+//	    type: dropdown
+//	    options: [Tokopedia, Shopee]
+//	    other: true
+//	  - section: Pengalaman       # mulai halaman/section baru
+//	  - text: Kepuasan
+//	    type: linear_scale
+//	    scale: {min: 1, max: 5, min_label: Buruk, max_label: Baik}
+//	  - text: Usia
+//	    validation:
+//	      - {type: number, operator: between, args: [17, 99]}
+//
+// Bisa juga memakai "sections: [{title, description, questions}]".
+type yamlQuestionnaire struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Sections    []yamlSection  `json:"sections"`
+	Questions   []yamlQuestion `json:"questions"`
+}
+
+type yamlSection struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Questions   []yamlQuestion `json:"questions"`
+}
+
+type yamlQuestion struct {
+	Section     *string       `json:"section"` // penanda section baru
+	Description string        `json:"description"`
+	Text        string        `json:"text"`
+	Help        string        `json:"help"`
+	Type        string        `json:"type"`
+	Required    bool          `json:"required"`
+	Options     []interface{} `json:"options"`
+	Other       bool          `json:"other"`
+	Rows        []interface{} `json:"rows"`
+	Scale       *struct {
+		Min      int64  `json:"min"`
+		Max      int64  `json:"max"`
+		MinLabel string `json:"min_label"`
+		MaxLabel string `json:"max_label"`
+	} `json:"scale"`
+	IncludeTime bool `json:"include_time"`
+	Duration    bool `json:"duration"`
+	Validation  []struct {
+		Type     string        `json:"type"`
+		Operator string        `json:"operator"`
+		Args     []interface{} `json:"args"`
+		Message  string        `json:"message"`
+	} `json:"validation"`
+	Image string `json:"image"`
+	Video string `json:"video"`
+}
+
+// yamlKindAliases: nama tipe yang umum dipakai selain nilai QuestionKind.
+var yamlKindAliases = map[string]QuestionKind{
+	"text": KindShortAnswer, "short": KindShortAnswer, "long_text": KindParagraph,
+	"textarea": KindParagraph, "radio": KindMultipleChoice, "single_choice": KindMultipleChoice,
+	"select": KindDropdown, "multiple_select": KindCheckbox, "checkboxes": KindCheckbox,
+	"scale": KindLinearScale, "rating": KindLinearScale, "matrix": KindGrid,
+	"matrix_checkbox": KindCheckboxGrid, "datetime": KindDate, "duration": KindTime, "file": KindFileUpload,
+}
+
+func yamlStrings(items []interface{}) []string {
+	var out []string
+	for _, it := range items {
+		if it != nil {
+			out = append(out, fmt.Sprint(it))
+		}
+	}
+	return out
+}
+
+func importYAML(content []byte) (*ImportResponse, error) {
+	var doc yamlQuestionnaire
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("JSON kuesioner tidak valid: %v", err)
+		}
+	} else {
+		var generic interface{}
+		if err := yaml.Unmarshal(content, &generic); err != nil {
+			return nil, fmt.Errorf("YAML kuesioner tidak valid: %v", err)
+		}
+		if generic == nil {
+			return nil, fmt.Errorf("dokumen YAML kosong")
+		}
+		// Bentuk generik (map/list/scalar) -> struct lewat JSON, agar tag
+		// json di yamlQuestion berlaku sama untuk input YAML & JSON
+		raw, err := json.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("struktur YAML tidak sesuai format kuesioner: %v", err)
+		}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("struktur YAML tidak sesuai format kuesioner: %v", err)
+		}
+	}
+
+	b := newImportBuilder(ImportSourceYAML, doc.Title, doc.Description)
+	addYAMLQuestions(b, doc.Questions)
+	for _, sec := range doc.Sections {
+		b.addPage(sec.Title, sec.Description)
+		addYAMLQuestions(b, sec.Questions)
+	}
+	return b.result()
+}
+
+func addYAMLQuestions(b *importBuilder, items []yamlQuestion) {
+	for _, y := range items {
+		switch {
+		case y.Section != nil:
+			b.addPage(*y.Section, y.Description)
+			continue
+		case y.Image != "":
+			b.addMedia(MediaItem{Kind: "image", Title: y.Text, URL: y.Image})
+			continue
+		case y.Video != "":
+			b.addMedia(MediaItem{Kind: "video", Title: y.Text, URL: y.Video})
+			continue
+		}
+
+		q := QuestionItem{
+			Text:     y.Text,
+			HelpText: y.Help,
+			Required: y.Required,
+			Options:  yamlStrings(y.Options),
+			HasOther: y.Other,
+		}
+		typ := strings.ToLower(strings.TrimSpace(y.Type))
+		if kind, ok := yamlKindAliases[typ]; ok {
+			q.Kind = kind
+		} else if typ == "" {
+			q.Kind = KindShortAnswer
+			if len(q.Options) > 0 {
+				q.Kind = KindMultipleChoice
+			}
+		} else {
+			q.Kind = QuestionKind(typ)
+		}
+		if _, known := kindLabels[q.Kind]; !known || q.Kind == KindUnknown {
+			b.warn("%q: tipe %q tidak dikenal, dilewati", y.Text, y.Type)
+			continue
+		}
+		if q.Text == "" {
+			b.warn("pertanyaan tanpa text dilewati")
+			continue
+		}
+
+		switch q.Kind {
+		case KindLinearScale:
+			q.Scale = &ScaleMeta{Min: 1, Max: 5}
+			if y.Scale != nil {
+				q.Scale = &ScaleMeta{Min: y.Scale.Min, Max: y.Scale.Max, MinLabel: y.Scale.MinLabel, MaxLabel: y.Scale.MaxLabel}
+			}
+			q.Options = nil
+		case KindGrid, KindCheckboxGrid:
+			for _, label := range yamlStrings(y.Rows) {
+				q.Rows = append(q.Rows, GridRow{Label: label})
+			}
+			if len(q.Rows) == 0 {
+				b.warn("%q: grid tanpa rows dilewati", y.Text)
+				continue
+			}
+		case KindDate:
+			q.Date = &DateMeta{IncludeYear: true, IncludeTime: y.IncludeTime || typ == "datetime"}
+		case KindTime:
+			q.Time = &TimeMeta{Duration: y.Duration || typ == "duration"}
+		}
+		for _, v := range y.Validation {
+			q.Validation = append(q.Validation, ValidationRule{Type: v.Type, Operator: v.Operator, Args: yamlStrings(v.Args), Message: v.Message})
+		}
+		b.addQuestion(q)
+	}
+}
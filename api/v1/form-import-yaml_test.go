@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const yamlQuestionnaireFixture = `---
+# Kuesioner contoh
+title: "Survei Belanja"
+description: >
+  Pilot study
+  belanja online
+options: &platforms [Tokopedia, Shopee]
+questions:
+  - text: Nama
+    type: text            # alias short_answer
+    required: true
+    help: |
+      Nama lengkap
+      sesuai KTP
+  - text: 'Platform favorit'
+    type: dropdown
+    options: *platforms
+    other: true
+  - section: Pengalaman
+  - text: Kepuasan
+    type: scale
+    scale: {min: 1, max: 10, min_label: Buruk, max_label: Baik}
+  - text: Usia
+    validation:
+      - {type: number, operator: between, args: [17, 99], message: "Usia 17-99"}
+`
+
+const jsonQuestionnaireFixture = `{
+  "title": "Survei Belanja",
+  "description": "Pilot study belanja online\n",
+  "questions": [
+    {"text": "Nama", "type": "text", "required": true, "help": "Nama lengkap\nsesuai KTP\n"},
+    {"text": "Platform favorit", "type": "dropdown", "options": ["Tokopedia", "Shopee"], "other": true},
+    {"section": "Pengalaman"},
+    {"text": "Kepuasan", "type": "scale", "scale": {"min": 1, "max": 10, "min_label": "Buruk", "max_label": "Baik"}},
+    {"text": "Usia", "validation": [{"type": "number", "operator": "between", "args": [17, 99], "message": "Usia 17-99"}]}
+  ]
+}`
+
+func TestImportYAMLMatchesJSON(t *testing.T) {
+	fromYAML, err := importYAML([]byte(yamlQuestionnaireFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := importYAML([]byte(jsonQuestionnaireFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, _ := json.Marshal(fromYAML)
+	j, _ := json.Marshal(fromJSON)
+	if string(y) != string(j) {
+		t.Errorf("YAML and JSON imports differ:\nyaml: %s\njson: %s", y, j)
+	}
+
+	qs := fromYAML.Questions
+	if len(qs) != 4 || len(fromYAML.Pages) != 2 {
+		t.Fatalf("got %d questions / %d pages, want 4 / 2", len(qs), len(fromYAML.Pages))
+	}
+	if qs[0].Kind != KindShortAnswer || !qs[0].Required || qs[0].HelpText != "Nama lengkap\nsesuai KTP\n" {
+		t.Errorf("question 0 = %+v", qs[0])
+	}
+	if !reflect.DeepEqual(qs[1].Options, []string{"Tokopedia", "Shopee"}) || !qs[1].HasOther {
+		t.Errorf("question 1 = %+v, want alias options and other", qs[1])
+	}
+	if qs[2].Scale == nil || qs[2].Scale.Max != 10 || qs[2].Scale.MaxLabel != "Baik" {
+		t.Errorf("question 2 scale = %+v", qs[2].Scale)
+	}
+	wantRule := []ValidationRule{{Type: "number", Operator: "between", Args: []string{"17", "99"}, Message: "Usia 17-99"}}
+	if !reflect.DeepEqual(qs[3].Validation, wantRule) {
+		t.Errorf("question 3 validation = %+v, want %+v", qs[3].Validation, wantRule)
+	}
+}
+
+func TestImportYAMLErrors(t *testing.T) {
+	cases := map[string]string{
+		"empty":          "# hanya komentar\n",
+		"invalid syntax": "questions:\n  - text: [Nama\n",
+		"wrong shape":    "questions: Nama\n",
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := importYAML([]byte(src)); err == nil {
+				t.Errorf("expected error for %q", src)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ======================================================
+// Questionnaire Import (non-Google)
+// ======================================================
+
+// Sumber saves hasil import. Saves dari Google Form memakai Source kosong;
+// saves hasil import hanya untuk generate data (factory / renderer /
+// schema), tidak bisa diinjeksi karena tidak punya form tujuan.
+const (
+	ImportSourceSurveyJS  = "surveyjs"
+	ImportSourceQualtrics = "qualtrics"
+	ImportSourceYAML      = "yaml"
+)
+
+// importBuilder menyusun model pertanyaan yang sama dengan hasil scrape.
+// Entry ID dibuat berurutan (sintetis) sesuai urutan item di dokumen,
+// jadi dokumen yang sama selalu menghasilkan ID yang sama.
+type importBuilder struct {
+	source    string
+	nextID    int64
+	position  int
+	questions []QuestionItem
+	pages     []FormPage
+	media     []MediaItem
+	warnings  []string
+}
+
+func newImportBuilder(source, title, description string) *importBuilder {
+	return &importBuilder{
+		source: source,
+		nextID: 1,
+		pages:  []FormPage{{Index: 0, Title: title, Description: description}},
+	}
+}
+
+func (b *importBuilder) id() int64 {
+	id := b.nextID
+	b.nextID++
+	return id
+}
+
+// addPage memulai section baru. Halaman pertama yang masih kosong dipakai
+// ulang (judulnya tidak ditimpa) agar tidak ada section kosong di depan.
+func (b *importBuilder) addPage(title, description string) {
+	last := &b.pages[len(b.pages)-1]
+	if len(b.pages) == 1 && len(last.EntryIDs) == 0 {
+		if last.Title == "" {
+			last.Title = title
+		}
+		if last.Description == "" {
+			last.Description = description
+		}
+		return
+	}
+	b.position++
+	b.pages = append(b.pages, FormPage{
+		Index:       len(b.pages),
+		ID:          b.id(),
+		Title:       title,
+		Description: description,
+	})
+}
+
+// addQuestion memberi ID (pertanyaan & baris grid), posisi, lalu
+// memasukkan pertanyaan ke halaman terakhir.
+func (b *importBuilder) addQuestion(q QuestionItem) {
+	if q.Kind == KindLinearScale && q.Scale != nil && len(q.Options) == 0 {
+		for n := q.Scale.Min; n <= q.Scale.Max; n++ {
+			q.Options = append(q.Options, strconv.FormatInt(n, 10))
+		}
+	}
+	if len(q.Rows) > 0 {
+		for i := range q.Rows {
+			q.Rows[i].ID = b.id()
+		}
+		// Sama seperti scrapper: ID pertanyaan grid = ID baris pertama
+		q.ID = q.Rows[0].ID
+	} else {
+		q.ID = b.id()
+	}
+	q.Position = b.position
+	b.position++
+
+	b.questions = append(b.questions, q)
+	current := &b.pages[len(b.pages)-1]
+	current.EntryIDs = append(current.EntryIDs, q.ID)
+}
+
+func (b *importBuilder) addMedia(m MediaItem) {
+	m.Position = b.position
+	m.PageIndex = len(b.pages) - 1
+	b.position++
+	b.media = append(b.media, m)
+}
+
+func (b *importBuilder) warn(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+// result menghasilkan response dengan bentuk yang sama seperti
+// ScrapperHandler (tanpa fbzx & form ID).
+func (b *importBuilder) result() (*ImportResponse, error) {
+	if len(b.questions) == 0 {
+		return nil, fmt.Errorf("dokumen %s tidak berisi pertanyaan yang didukung", b.source)
+	}
+
+	var entryIDs []int64
+	entryMappings := make(map[string]int64)
+	for _, q := range b.questions {
+		entryIDs = append(entryIDs, q.ID)
+		if q.Text != "" {
+			entryMappings[q.Text] = q.ID
+		}
+	}
+	var pageHistoryParts []string
+	for i := range b.pages {
+		pageHistoryParts = append(pageHistoryParts, strconv.Itoa(i))
+	}
+	settings := FormSettings{EmailCollection: EmailCollectionNone}
+
+	return &ImportResponse{
+		ScrapeResponse: &ScrapeResponse{
+			Description: b.pages[0].Description,
+			Questions:   b.questions,
+			Pages:       b.pages,
+			Media:       b.media,
+			Settings:    settings,
+			Saves: FormSaveState{
+				Source:        b.source,
+				PageHistory:   strings.Join(pageHistoryParts, ","),
+				EntryIDs:      entryIDs,
+				EntryMappings: entryMappings,
+				Questions:     b.questions,
+				Pages:         b.pages,
+				Media:         b.media,
+				SchemaHash:    computeSchemaHash(b.questions, b.pages),
+				Settings:      &settings,
+			},
+		},
+		Source:   b.source,
+		Warnings: b.warnings,
+	}, nil
+}
+
+var reHTMLTag = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// stripHTML membersihkan teks pertanyaan berformat HTML (Qualtrics,
+// SurveyJS markdown/HTML) menjadi teks polos satu spasi.
+func stripHTML(s string) string {
+	s = reHTMLTag.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// ======================================================
+// HTTP Handler
+// ======================================================
+
+// ImportRequest: Content berisi dokumen sumber. Untuk surveyjs/qualtrics
+// boleh object JSON langsung atau string JSON; untuk yaml berupa string.
+type ImportRequest struct {
+	Format  string          `json:"format"` // surveyjs | qualtrics | yaml
+	Content json.RawMessage `json:"content"`
+}
+
+type ImportResponse struct {
+	*ScrapeResponse
+	Source   string   `json:"source"`
+	Warnings []string `json:"warnings,omitempty"` // item yang dilewati / disederhanakan
+}
+
+// importQuestionnaire memilih importer sesuai format.
+func importQuestionnaire(format string, content []byte) (*ImportResponse, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case ImportSourceSurveyJS:
+		return importSurveyJS(content)
+	case ImportSourceQualtrics, "qsf":
+		return importQualtrics(content)
+	case ImportSourceYAML, "yml":
+		return importYAML(content)
+	}
+	return nil, fmt.Errorf("format %q tidak dikenal (pilih %s, %s, atau %s)", format, ImportSourceSurveyJS, ImportSourceQualtrics, ImportSourceYAML)
+}
+
+// ImportHandler menerima JSON {format, content}, atau dokumen mentah
+// dengan ?format=... (misal upload file .qsf / .yaml apa adanya).
+func ImportHandler(w http.ResponseWriter, r *http.Request) {
+	if err := mustAuthorize(r); err != nil {
+		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		http.Error(w, "failed to read body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	content := body
+	if format == "" {
+		var req ImportRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "invalid json body (atau kirim dokumen mentah dengan ?format=): "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		format = req.Format
+		content = req.Content
+		// Content berupa string JSON (misal isi file YAML): ambil isinya
+		var text string
+		if json.Unmarshal(req.Content, &text) == nil {
+			content = []byte(text)
+		}
+	}
+	if len(content) == 0 {
+		http.Error(w, "content is required", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := importQuestionnaire(format, content)
+	if err != nil {
+		http.Error(w, "import failed: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ======================================================
+// Async Injection Jobs
+// ======================================================
+
+// Untuk answers besar, InjectorHandler bisa terpotong timeout Cloud Run.
+// Job API: submit langsung dijawab job ID, baris dikirim di background,
+// progress & hasil per baris dibaca lewat endpoint status.
+//
+// Catatan Cloud Run: goroutine background butuh "CPU always allocated"
+// agar tetap jalan setelah response submit terkirim.
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusCanceled  = "canceled"
+)
+
+type InjectJob struct {
+	ID         string     `json:"job_id"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	Total           int  `json:"total"`     // semua baris (termasuk invalid)
+	Processed       int  `json:"processed"` // baris yang sudah punya hasil
+	Success         int  `json:"success"`
+	Failed          int  `json:"failed"`
+	Invalid         int  `json:"invalid"`
+	Canceled        int  `json:"canceled"`
+	CancelRequested bool `json:"cancel_requested,omitempty"`
+
+	// Rows: hasil per baris sesuai urutan selesai; setelah job selesai
+	// diganti hasil lengkap urut index baris.
+	Rows []InjectRowResult `json:"rows"`
+	// Result: ringkasan akhir (details, outcomes, violations), tanpa Rows
+	Result *InjectResult `json:"result,omitempty"`
+}
+
+func (j *InjectJob) finished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusCanceled
+}
+
+// record menambahkan hasil satu baris ke progress job.
+func (j *InjectJob) record(res InjectRowResult) {
+	j.Rows = append(j.Rows, res)
+	j.Processed++
+	switch res.Status {
+	case RowStatusSuccess:
+		j.Success++
+	case RowStatusCanceled:
+		j.Canceled++
+	case RowStatusInvalid:
+		j.Invalid++
+	default:
+		j.Failed++
+	}
+}
+
+// --- Job Store ---
+
+var ErrInjectJobNotFound = errors.New("injection job not found")
+
+// InjectJobStore menyimpan state job. Default in-memory (hanya satu
+// instance); store bersama (Redis, Firestore, ...) dipasang lewat
+// SetInjectJobStore agar status & cancel bisa dari instance mana pun.
+type InjectJobStore interface {
+	Create(job InjectJob) error
+	// Get mengembalikan salinan job atau ErrInjectJobNotFound.
+	Get(id string) (InjectJob, error)
+	// Update menjalankan fn secara atomik terhadap job tersimpan.
+	Update(id string, fn func(*InjectJob)) error
+}
+
+var (
+	injectJobStoreMu sync.RWMutex
+	injectJobStore   InjectJobStore = newMemoryJobStoreFromEnv()
+)
+
+// SetInjectJobStore mengganti store job (panggil sebelum server start).
+func SetInjectJobStore(s InjectJobStore) {
+	injectJobStoreMu.Lock()
+	defer injectJobStoreMu.Unlock()
+	injectJobStore = s
+}
+
+func jobStore() InjectJobStore {
+	injectJobStoreMu.RLock()
+	defer injectJobStoreMu.RUnlock()
+	return injectJobStore
+}
+
+// memoryJobStore: store default. Job yang sudah selesai dihapus setelah
+// ttl (DATAFACT_INJECT_JOB_TTL, default 1h) saat job baru dibuat.
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*InjectJob
+	ttl  time.Duration
+}
+
+func newMemoryJobStoreFromEnv() *memoryJobStore {
+	ttl, err := time.ParseDuration(getenv("DATAFACT_INJECT_JOB_TTL", "1h"))
+	if err != nil {
+		ttl = time.Hour
+	}
+	return &memoryJobStore{jobs: make(map[string]*InjectJob), ttl: ttl}
+}
+
+func (m *memoryJobStore) Create(job InjectJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for id, j := range m.jobs {
+		if j.FinishedAt != nil && now.Sub(*j.FinishedAt) > m.ttl {
+			delete(m.jobs, id)
+		}
+	}
+	m.jobs[job.ID] = &job
+	return nil
+}
+
+func (m *memoryJobStore) Get(id string) (InjectJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return InjectJob{}, ErrInjectJobNotFound
+	}
+	out := *j
+	out.Rows = append([]InjectRowResult(nil), j.Rows...)
+	return out, nil
+}
+
+func (m *memoryJobStore) Update(id string, fn func(*InjectJob)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return ErrInjectJobNotFound
+	}
+	fn(j)
+	return nil
+}
+
+// jobCancels: cancel func job yang berjalan di instance ini.
+var jobCancels sync.Map // job ID -> context.CancelFunc
+
+func newJobID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// startInjectJob mendaftarkan job lalu menjalankan plan di background.
+func startInjectJob(plan *injectPlan) (InjectJob, error) {
+	store := jobStore()
+	job := InjectJob{
+		ID:        newJobID(),
+		Status:    JobStatusQueued,
+		CreatedAt: time.Now(),
+		Total:     plan.total,
+	}
+	for _, res := range plan.invalid {
+		job.record(res)
+	}
+	if err := store.Create(job); err != nil {
+		return InjectJob{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobCancels.Store(job.ID, cancel)
+	go runInjectJob(ctx, cancel, store, job.ID, plan)
+	return job, nil
+}
+
+func runInjectJob(ctx context.Context, cancel context.CancelFunc, store InjectJobStore, id string, plan *injectPlan) {
+	defer func() {
+		jobCancels.Delete(id)
+		cancel()
+	}()
+
+	started := time.Now()
+	store.Update(id, func(j *InjectJob) {
+		j.Status = JobStatusRunning
+		j.StartedAt = &started
+		// Cancel sebelum sempat mulai
+		if j.CancelRequested {
+			cancel()
+		}
+	})
+
+	result := runInjection(ctx, plan, func(res InjectRowResult) {
+		store.Update(id, func(j *InjectJob) {
+			j.record(res)
+			// Flag cancel dari instance lain (store bersama)
+			if j.CancelRequested {
+				cancel()
+			}
+		})
+	})
+
+	finished := time.Now()
+	store.Update(id, func(j *InjectJob) {
+		j.Status = JobStatusCompleted
+		if result.Canceled > 0 {
+			j.Status = JobStatusCanceled
+		}
+		j.FinishedAt = &finished
+		j.Rows = result.Rows
+		j.Success, j.Failed, j.Invalid, j.Canceled = result.Success, result.Failed, result.Invalid, result.Canceled
+		j.Processed = len(result.Rows)
+
+		summary := result
+		summary.Rows = nil
+		j.Result = &summary
+	})
+}
+
+// ======================================================
+// HTTP Handler
+// ======================================================
+
+// InjectJobHandler:
+//   - POST: body sama dengan InjectorHandler, jawab 202 + job (tanpa menunggu submit)
+//   - GET ?id=<job_id>: status, progress & hasil per baris
+func InjectJobHandler(w http.ResponseWriter, r *http.Request) {
+	if err := mustAuthorize(r); err != nil {
+		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req InjectRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid json body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.DryRun {
+			http.Error(w, "dry_run is not supported for jobs, use /api/v1/form-injector", http.StatusBadRequest)
+			return
+		}
+
+		// Validasi tetap sinkron agar error request langsung terlihat
+		plan, perr := prepareInjection(req)
+		if perr != nil {
+			writeInjectPrepError(w, perr)
+			return
+		}
+		job, err := startInjectJob(plan)
+		if err != nil {
+			http.Error(w, "failed to create job: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/form-injector-job?id="+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+
+	case http.MethodGet:
+		job, ok := lookupJob(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+
+	default:
+		http.Error(w, "use POST or GET", http.StatusMethodNotAllowed)
+	}
+}
+
+// InjectJobCancelHandler: POST ?id=<job_id>. Baris yang belum dikirim
+// dibatalkan; request yang sedang berjalan tetap diselesaikan.
+func InjectJobCancelHandler(w http.ResponseWriter, r *http.Request) {
+	if err := mustAuthorize(r); err != nil {
+		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	err := jobStore().Update(id, func(j *InjectJob) {
+		if !j.finished() {
+			j.CancelRequested = true
+		}
+	})
+	if err != nil {
+		writeJobStoreError(w, err)
+		return
+	}
+	if cancel, ok := jobCancels.Load(id); ok {
+		cancel.(context.CancelFunc)()
+	}
+
+	job, ok := lookupJob(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func lookupJob(w http.ResponseWriter, r *http.Request) (InjectJob, bool) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return InjectJob{}, false
+	}
+	job, err := jobStore().Get(id)
+	if err != nil {
+		writeJobStoreError(w, err)
+		return InjectJob{}, false
+	}
+	return job, true
+}
+
+func writeJobStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrInjectJobNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, "job store error: "+err.Error(), http.StatusInternalServerError)
+}
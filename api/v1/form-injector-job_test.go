@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startJobForm menjalankan endpoint formResponse palsu. Setiap kiriman
+// menunggu release ditutup sebelum dijawab halaman konfirmasi.
+func startJobForm(t *testing.T, release chan struct{}) *int32 {
+	t.Helper()
+	var submits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&submits, 1)
+		<-release
+		w.Write([]byte("<html>Your response has been recorded.</html>"))
+	}))
+	t.Cleanup(srv.Close)
+	prevBase := googleFormsBase
+	googleFormsBase = srv.URL + "/forms"
+	t.Cleanup(func() { googleFormsBase = prevBase })
+
+	prevStore := jobStore()
+	SetInjectJobStore(newMemoryJobStoreFromEnv())
+	t.Cleanup(func() { SetInjectJobStore(prevStore) })
+	t.Setenv("DATAFACT_API_KEY", "test-key")
+	return &submits
+}
+
+func jobRequest(method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer test-key")
+	return r
+}
+
+func submitJob(t *testing.T, rows int) InjectJob {
+	t.Helper()
+	var answers []string
+	for i := 0; i < rows; i++ {
+		answers = append(answers, fmt.Sprintf(`{"Nama":"R%d"}`, i))
+	}
+	// Baris terakhir invalid: langsung tercatat saat submit
+	answers = append(answers, `{"bogus":"x"}`)
+	body := `{"saves":{"form_id":"1FAIpQLSjob","fbzx":"1","entry_ids":[11],"entry_mappings":{"Nama":11},
+		"questions":[{"id":11,"text":"Nama","kind":"short_answer"}]},"answers":[` + strings.Join(answers, ",") + `]}`
+
+	rec := httptest.NewRecorder()
+	InjectJobHandler(rec, jobRequest(http.MethodPost, "/api/v1/form-injector-job", body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit status = %d: %s", rec.Code, rec.Body)
+	}
+	var job InjectJob
+	if err := json.NewDecoder(rec.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Header().Get("Location") != "/api/v1/form-injector-job?id="+job.ID {
+		t.Errorf("location = %q", rec.Header().Get("Location"))
+	}
+	if job.Total != rows+1 || job.Invalid != 1 {
+		t.Errorf("submitted job total/invalid = %d/%d, want %d/1", job.Total, job.Invalid, rows+1)
+	}
+	return job
+}
+
+func pollJob(t *testing.T, id string) InjectJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		InjectJobHandler(rec, jobRequest(http.MethodGet, "/api/v1/form-injector-job?id="+id, ""))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("poll status = %d: %s", rec.Code, rec.Body)
+		}
+		var job InjectJob
+		if err := json.NewDecoder(rec.Body).Decode(&job); err != nil {
+			t.Fatal(err)
+		}
+		if job.finished() {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job still %s after 5s: %+v", job.Status, job)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInjectJobCompletes(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	submits := startJobForm(t, release)
+
+	job := pollJob(t, submitJob(t, 3).ID)
+	if job.Status != JobStatusCompleted || job.Success != 3 || job.Invalid != 1 || job.Processed != 4 {
+		t.Errorf("job = %+v, want completed with 3 success + 1 invalid", job)
+	}
+	if job.Result == nil || job.Result.Rows != nil || len(job.Rows) != 4 {
+		t.Errorf("result = %+v, rows = %d, want summary without rows + 4 rows", job.Result, len(job.Rows))
+	}
+	for i, row := range job.Rows {
+		if row.Row != i {
+			t.Errorf("rows[%d].row = %d, want index order", i, row.Row)
+		}
+	}
+	if *submits != 3 {
+		t.Errorf("submits = %d, want 3", *submits)
+	}
+}
+
+func TestInjectJobCancel(t *testing.T) {
+	release := make(chan struct{})
+	submits := startJobForm(t, release)
+
+	// 15 baris, 10 berjalan (tertahan di server), 5 menunggu slot
+	job := submitJob(t, 15)
+	for deadline := time.Now().Add(5 * time.Second); atomic.LoadInt32(submits) < 10; {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d rows in flight", atomic.LoadInt32(submits))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	InjectJobCancelHandler(rec, jobRequest(http.MethodPost, "/api/v1/form-injector-job/cancel?id="+job.ID, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cancel status = %d: %s", rec.Code, rec.Body)
+	}
+	close(release)
+
+	job = pollJob(t, job.ID)
+	if job.Status != JobStatusCanceled || !job.CancelRequested {
+		t.Errorf("status = %s (cancel_requested %v), want canceled", job.Status, job.CancelRequested)
+	}
+	// Kiriman yang sudah berjalan tetap diselesaikan
+	if job.Success != 10 || job.Canceled != 5 || job.Invalid != 1 {
+		t.Errorf("success/canceled/invalid = %d/%d/%d, want 10/5/1", job.Success, job.Canceled, job.Invalid)
+	}
+	if *submits != 10 {
+		t.Errorf("submits = %d, want 10", *submits)
+	}
+}
+
+func TestInjectJobHandlerErrors(t *testing.T) {
+	startJobForm(t, make(chan struct{}))
+	cases := []struct {
+		name   string
+		h      http.HandlerFunc
+		req    *http.Request
+		status int
+	}{
+		{"unknown job", InjectJobHandler, jobRequest(http.MethodGet, "/api/v1/form-injector-job?id=nope", ""), http.StatusNotFound},
+		{"missing id", InjectJobHandler, jobRequest(http.MethodGet, "/api/v1/form-injector-job", ""), http.StatusBadRequest},
+		{"dry run", InjectJobHandler, jobRequest(http.MethodPost, "/api/v1/form-injector-job", `{"dry_run":true}`), http.StatusBadRequest},
+		{"cancel unknown", InjectJobCancelHandler, jobRequest(http.MethodPost, "/api/v1/form-injector-job/cancel?id=nope", ""), http.StatusNotFound},
+		{"cancel with GET", InjectJobCancelHandler, jobRequest(http.MethodGet, "/api/v1/form-injector-job/cancel?id=nope", ""), http.StatusMethodNotAllowed},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		c.h(rec, c.req)
+		if rec.Code != c.status {
+			t.Errorf("%s: status = %d, want %d", c.name, rec.Code, c.status)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/form-injector-job?id=nope", nil)
+	InjectJobHandler(rec, r)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("no auth: status = %d, want 401", rec.Code)
+	}
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,16 +19,108 @@ import (
 // --- Models Injector ---
 
 type InjectRequest struct {
+	// Link form apa pun (viewform/edit/prefilled/forms.gle). Boleh kosong
+	// jika saves.form_id sudah kanonik.
 	FormURL string          `json:"form_url"`
 	Saves   json.RawMessage `json:"saves"`
 	Answers json.RawMessage `json:"answers"`
+
+	// Layout Go tambahan untuk jawaban tanggal/waktu (selain ISO-8601)
+	DateLayouts []string `json:"date_layouts,omitempty"`
+
+	// VerifySchema: scrape ulang form live sebelum submit dan tolak jika
+	// struktur form sudah berbeda dari saves.
+	VerifySchema bool `json:"verify_schema,omitempty"`
+	// Refresh: verifikasi ke form live tanpa cache skema (form-cache.go)
+	Refresh bool `json:"refresh,omitempty"`
+
+	// DryRun: jalankan normalisasi, validasi & build payload lalu kembalikan
+	// body formResponse per baris tanpa submit ke form. Dry run tidak
+	// menghubungi Google sama sekali: link forms.gle / edit tidak dibuka
+	// (target diambil dari saves.form_id) dan verify_schema ditolak.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Stream: "ndjson" | "sse" -> event "row" per baris selesai lalu
+	// "summary" (progress-stream.go). Kosong = satu response JSON.
+	Stream string `json:"stream,omitempty"`
+}
+
+// StaleSavesResponse: body response 409 saat saves tidak cocok dengan form live.
+type StaleSavesResponse struct {
+	Error     string     `json:"error"`
+	Message   string     `json:"message"`
+	SavedHash string     `json:"saved_hash"`
+	LiveHash  string     `json:"live_hash"`
+	Diff      SchemaDiff `json:"diff"`
 }
 
 type InjectResult struct {
 	Total   int      `json:"total"`
 	Success int      `json:"success"`
 	Failed  int      `json:"failed"`
+	Invalid int      `json:"invalid"` // baris yang gagal validasi (tidak dikirim)
 	Details []string `json:"details"`
+
+	// Canceled: baris yang tidak jadi dikirim karena request/job dibatalkan
+	Canceled int `json:"canceled,omitempty"`
+
+	// Rows: hasil per baris input, urut sesuai index baris
+	Rows       []InjectRowResult `json:"rows"`
+	Outcomes   map[string]int    `json:"outcomes,omitempty"` // jumlah baris terkirim per outcome
+	Violations []RowViolations   `json:"violations,omitempty"`
+
+	DryRun   bool            `json:"dry_run,omitempty"`
+	Payloads []DryRunPayload `json:"payloads,omitempty"` // hanya saat dry_run
+}
+
+// Status baris di InjectRowResult
+const (
+	RowStatusSuccess  = "success"
+	RowStatusFailed   = "failed"
+	RowStatusInvalid  = "invalid"  // gagal validasi, tidak dikirim
+	RowStatusDryRun   = "dry_run"  // payload dibangun, tidak dikirim
+	RowStatusCanceled = "canceled" // dibatalkan sebelum dikirim
+)
+
+// InjectRowResult: hasil satu baris jawaban, untuk rekonsiliasi input vs
+// hasil submit.
+type InjectRowResult struct {
+	Row        int    `json:"row"` // index baris pada input answers
+	Status     string `json:"status"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Snippet    string `json:"snippet,omitempty"` // potongan body response
+	Error      string `json:"error,omitempty"`
+	// Outcome: hasil submit menurut isi response (lihat SubmitOutcome*)
+	Outcome string `json:"outcome,omitempty"`
+
+	SentEntryIDs    []int64  `json:"sent_entry_ids,omitempty"`
+	DroppedKeys     []string `json:"dropped_keys,omitempty"`      // key jawaban yang tidak cocok dengan form
+	SkippedEntryIDs []int64  `json:"skipped_entry_ids,omitempty"` // jawaban di section yang tidak dilalui
+}
+
+func newInjectRowResult(row injectRow, status string) InjectRowResult {
+	return InjectRowResult{
+		Row:             row.Index,
+		Status:          status,
+		DroppedKeys:     row.Unknown,
+		SkippedEntryIDs: row.Skipped,
+	}
+}
+
+func sortRowResults(rows []InjectRowResult) {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Row < rows[j].Row })
+}
+
+// DryRunPayload: body yang akan dikirim untuk satu baris valid.
+type DryRunPayload struct {
+	Row             int                 `json:"row"`
+	ResponseURL     string              `json:"response_url"`
+	Body            string              `json:"body"` // application/x-www-form-urlencoded
+	PartialResponse json.RawMessage     `json:"partial_response"`
+	PageHistory     string              `json:"page_history"`
+	Fbzx            string              `json:"fbzx"`
+	Entries         map[string][]string `json:"entries"` // entry.<ID> -> nilai yang dikirim
 }
 
 // --- Helper Functions ---
@@ -37,32 +133,688 @@ func parseFlexibleJSON(raw json.RawMessage, target interface{}) error {
 	return json.Unmarshal(raw, target)
 }
 
-// --- Handler ---
+// indexQuestions memetakan entry ID -> pertanyaan. Baris grid ikut
+// dipetakan (ke pertanyaan induknya) agar ID baris dianggap valid.
+func indexQuestions(saves FormSaveState) map[int64]QuestionItem {
+	idx := make(map[int64]QuestionItem, len(saves.Questions))
+	for _, q := range saves.Questions {
+		idx[q.ID] = q
+		for _, row := range q.Rows {
+			idx[row.ID] = q
+		}
+	}
+	return idx
+}
 
-func InjectorHandler(w http.ResponseWriter, r *http.Request) {
-	if err := mustAuthorize(r); err != nil {
-		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
-		return
+// expandGridAnswers mengubah jawaban grid berbentuk object
+// {"Label Baris": "Kolom"} (atau {"Label Baris": ["A", "B"]} untuk
+// checkbox grid) menjadi satu entry per baris. Label baris yang tidak
+// dikenal dikembalikan sebagai unknown, jawaban non-grid dibiarkan.
+func expandGridAnswers(rowMap map[int64]interface{}, questions map[int64]QuestionItem) (map[int64]interface{}, []string) {
+	out := make(map[int64]interface{}, len(rowMap))
+	var unknown []string
+	for id, val := range rowMap {
+		q, found := questions[id]
+		byRow, isObject := val.(map[string]interface{})
+		if !found || len(q.Rows) == 0 || !isObject {
+			out[id] = val
+			continue
+		}
+		for label, cell := range byRow {
+			if row, ok := q.RowByLabel(label); ok {
+				out[row.ID] = cell
+			} else {
+				unknown = append(unknown, q.Text+" ["+label+"]")
+			}
+		}
 	}
+	return out, unknown
+}
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "use POST", http.StatusMethodNotAllowed)
-		return
+// otherOptionValue: nilai yang dikirim Google untuk opsi "Other:",
+// teks isiannya dikirim di field entry.<ID>.other_option_response.
+const otherOptionValue = "__other_option__"
+
+// extractOtherAnswers memisahkan jawaban "Other" pada pertanyaan yang
+// punya opsi Other. Jawaban bisa {"other": "..."} atau nilai yang tidak
+// cocok dengan opsi mana pun. Nilainya diganti otherOptionValue dan teks
+// isiannya dikembalikan per entry ID. Google hanya menerima satu teks
+// Other per pertanyaan, jadi lebih dari satu teks berbeda = violation.
+func extractOtherAnswers(answers map[int64]interface{}, questions map[int64]QuestionItem) (map[int64]string, []Violation) {
+	others := make(map[int64]string)
+	var vs []Violation
+
+	ids := make([]int64, 0, len(answers))
+	for id := range answers {
+		ids = append(ids, id)
 	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
 
-	// 1. Decode Wrapper
-	var req InjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json body: "+err.Error(), http.StatusBadRequest)
+	for _, id := range ids {
+		q, ok := questions[id]
+		if !ok || !q.HasOther {
+			continue
+		}
+
+		var values, texts []string
+		addOther := func(s string) {
+			if !containsString(texts, s) {
+				texts = append(texts, s)
+			}
+		}
+		collect := func(v interface{}) {
+			if m, ok := v.(map[string]interface{}); ok {
+				if o, ok := m["other"]; ok && o != nil {
+					addOther(fmt.Sprintf("%v", o))
+				}
+				return
+			}
+			for _, s := range answerStrings(v) {
+				if containsString(q.Options, s) {
+					values = append(values, s)
+				} else {
+					addOther(s)
+				}
+			}
+		}
+		if list, ok := answers[id].([]interface{}); ok {
+			for _, item := range list {
+				collect(item)
+			}
+		} else {
+			collect(answers[id])
+		}
+
+		if len(texts) == 0 {
+			continue
+		}
+		if len(texts) > 1 {
+			vs = append(vs, Violation{
+				EntryID:  id,
+				Question: q.Text,
+				Level:    violationError,
+				Code:     "multiple_other",
+				Message:  fmt.Sprintf("hanya satu jawaban Other yang bisa dikirim, diterima %d: %q", len(texts), texts),
+			})
+		}
+		others[id] = texts[0]
+		answers[id] = append(values, otherOptionValue)
+	}
+	return others, vs
+}
+
+// --- Date/Time Encoding ---
+
+// encodeDateTimeAnswer mengubah jawaban tanggal/waktu menjadi nilai untuk
+// partialResponse + field komponen yang diharapkan Google
+// (entry.<ID>_year, _month, _day, _hour, _minute, _second).
+func encodeDateTimeAnswer(q QuestionItem, entryID int64, raw string, layouts []string) (string, url.Values, error) {
+	fields := url.Values{}
+	key := func(part string) string { return fmt.Sprintf("entry.%d_%s", entryID, part) }
+	pad := func(n int) string { return fmt.Sprintf("%02d", n) }
+
+	switch q.Kind {
+	case KindDate:
+		t, err := parseDateAnswer(raw, layouts)
+		if err != nil {
+			return "", nil, err
+		}
+		includeYear, includeTime := true, false
+		if q.Date != nil {
+			includeYear, includeTime = q.Date.IncludeYear, q.Date.IncludeTime
+		}
+		value := t.Format("2006-01-02")
+		if includeYear {
+			fields.Set(key("year"), strconv.Itoa(t.Year()))
+		} else {
+			value = t.Format("01-02")
+		}
+		fields.Set(key("month"), pad(int(t.Month())))
+		fields.Set(key("day"), pad(t.Day()))
+		if includeTime {
+			fields.Set(key("hour"), pad(t.Hour()))
+			fields.Set(key("minute"), pad(t.Minute()))
+			value += " " + t.Format("15:04")
+		}
+		return value, fields, nil
+
+	case KindTime:
+		if q.Time != nil && q.Time.Duration {
+			d, err := parseDurationAnswer(raw)
+			if err != nil {
+				return "", nil, err
+			}
+			h, m, sec := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+			fields.Set(key("hour"), pad(h))
+			fields.Set(key("minute"), pad(m))
+			fields.Set(key("second"), pad(sec))
+			return fmt.Sprintf("%s:%s:%s", pad(h), pad(m), pad(sec)), fields, nil
+		}
+		t, err := parseTimeAnswer(raw, layouts)
+		if err != nil {
+			return "", nil, err
+		}
+		fields.Set(key("hour"), pad(t.Hour()))
+		fields.Set(key("minute"), pad(t.Minute()))
+		return t.Format("15:04"), fields, nil
+	}
+
+	return raw, fields, nil
+}
+
+// --- Branching Helpers ---
+
+// branchAnswer mengambil jawaban tunggal (string) untuk dicocokkan
+// dengan opsi branching. Jawaban Other ({"other": ...} atau teks di luar
+// opsi) dipetakan ke otherOptionValue, key GoTo untuk opsi "Other:".
+func branchAnswer(q QuestionItem, val interface{}) (string, bool) {
+	var ans string
+	switch v := val.(type) {
+	case nil:
+		return "", false
+	case []interface{}:
+		if len(v) == 0 {
+			return "", false
+		}
+		return branchAnswer(q, v[0])
+	case []string:
+		if len(v) == 0 {
+			return "", false
+		}
+		ans = v[0]
+	case map[string]interface{}:
+		if _, ok := v["other"]; !ok {
+			return "", false
+		}
+		ans = otherOptionValue
+	default:
+		ans = fmt.Sprintf("%v", v)
+	}
+	if q.HasOther && ans != otherOptionValue && !containsString(q.Options, ans) {
+		ans = otherOptionValue
+	}
+	return ans, true
+}
+
+// resolvePagePath menghitung urutan halaman yang benar-benar dilalui
+// responden berdasarkan jawaban baris ini (logika "go to section").
+// Mengembalikan index halaman sesuai urutan kunjungan.
+func resolvePagePath(pages []FormPage, answers map[int64]interface{}, questions map[int64]QuestionItem) []int {
+	pageIndex := make(map[int64]int, len(pages))
+	for i, p := range pages {
+		if p.ID != 0 {
+			pageIndex[p.ID] = i
+		}
+	}
+
+	resolve := func(current int, target int64) int {
+		if target == navSubmit {
+			return -1
+		}
+		if idx, ok := pageIndex[target]; ok {
+			return idx
+		}
+		return current + 1
+	}
+
+	var path []int
+	// Batas kunjungan mencegah loop tak berujung jika form melompat mundur
+	for i := 0; i >= 0 && i < len(pages) && len(path) <= len(pages)*2; {
+		path = append(path, i)
+		next := i + 1
+		if pages[i].GoTo != 0 {
+			next = resolve(i, pages[i].GoTo)
+		}
+		// Pertanyaan branching terakhir yang dijawab di halaman ini menentukan tujuan
+		for _, id := range pages[i].EntryIDs {
+			q := questions[id]
+			if len(q.GoTo) == 0 {
+				continue
+			}
+			if ans, ok := branchAnswer(q, answers[id]); ok {
+				if target, ok := q.GoTo[ans]; ok {
+					next = resolve(i, target)
+				}
+			}
+		}
+		i = next
+	}
+	return path
+}
+
+// reachedEntries: set entry ID pertanyaan pada halaman-halaman di path.
+func reachedEntries(pages []FormPage, path []int) map[int64]bool {
+	reached := make(map[int64]bool)
+	for _, idx := range path {
+		for _, id := range pages[idx].EntryIDs {
+			reached[id] = true
+		}
+	}
+	return reached
+}
+
+// pruneUnreachedAnswers membuang jawaban untuk pertanyaan di halaman yang
+// tidak dilalui. Mengembalikan jawaban tersisa + entry ID yang dibuang
+// (terurut).
+func pruneUnreachedAnswers(answers map[int64]interface{}, reached map[int64]bool, questions map[int64]QuestionItem) (map[int64]interface{}, []int64) {
+	out := make(map[int64]interface{}, len(answers))
+	var skipped []int64
+	for id, val := range answers {
+		qID := id
+		if q, ok := questions[id]; ok {
+			qID = q.ID // ID baris grid -> ID pertanyaan induk
+		}
+		if !reached[qID] {
+			skipped = append(skipped, id)
+			continue
+		}
+		out[id] = val
+	}
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i] < skipped[j] })
+	return out, skipped
+}
+
+func formatPageHistory(path []int) string {
+	parts := make([]string, len(path))
+	for i, idx := range path {
+		parts[i] = strconv.Itoa(idx)
+	}
+	return strings.Join(parts, ",")
+}
+
+// resolveInjectTarget menentukan form tujuan dari form_url dan/atau
+// saves.form_id. Saves lama ("scraped_<unix>") tidak punya ID kanonik
+// sehingga form_url wajib diisi.
+func resolveInjectTarget(formURL, savedID string, offline bool) (FormRef, error) {
+	legacyID := savedID == "" || strings.HasPrefix(savedID, "scraped_")
+	if strings.TrimSpace(formURL) == "" {
+		if legacyID {
+			return FormRef{}, newScrapeError(ErrCodeInvalidURL, "form_url is required when saves has no form_id", nil)
+		}
+		return newFormRef(savedID), nil
+	}
+
+	ref, redirect, err := parseFormURL(formURL)
+	if err != nil {
+		return FormRef{}, err
+	}
+	if redirect != "" {
+		// offline (dry_run): link forms.gle / edit tidak dibuka, pakai
+		// form_id saves jika ada
+		if offline {
+			if legacyID {
+				return FormRef{}, newScrapeError(ErrCodeInvalidURL, "dry_run does not resolve forms.gle or edit links, pass a viewform link or saves with form_id", nil)
+			}
+			return newFormRef(savedID), nil
+		}
+		if ref, err = followFormRedirect(redirect); err != nil {
+			return FormRef{}, err
+		}
+	}
+	if !legacyID && ref.ID != savedID {
+		return FormRef{}, newScrapeError(ErrCodeInvalidURL, fmt.Sprintf("form_url points to form %s but saves belong to form %s", ref.ID, savedID), nil)
+	}
+	return ref, nil
+}
+
+// checkSavesFresh membandingkan hash saves dengan form live. Mengembalikan
+// nil jika sama, atau detail perubahan jika form sudah diedit.
+func checkSavesFresh(saves FormSaveState, target FormRef, refresh bool) (*StaleSavesResponse, error) {
+	savedHash := saves.SchemaHash
+	if savedHash == "" {
+		if len(saves.Questions) == 0 {
+			return nil, fmt.Errorf("saves has no schema to verify, re-scrape the form first")
+		}
+		savedHash = computeSchemaHash(saves.Questions, saves.Pages)
+	}
+
+	// Selalu ke upstream: cache fresh bisa menyembunyikan perubahan form
+	live, err := fetchFormSchema(target.ViewformURL, refresh, true)
+	if err != nil {
+		return nil, err
+	}
+	if live.Saves.SchemaHash == savedHash {
+		return nil, nil
+	}
+
+	return &StaleSavesResponse{
+		Error:     "stale_saves",
+		Message:   "form structure changed since saves were scraped",
+		SavedHash: savedHash,
+		LiveHash:  live.Saves.SchemaHash,
+		Diff:      diffSchemas(saves.Questions, live.Questions, saves.Pages, live.Saves.Pages),
+	}, nil
+}
+
+// checkFormSettings menolak injeksi yang pasti gagal berdasarkan
+// pengaturan form: wajib sign-in atau sudah tidak menerima respon.
+func checkFormSettings(settings *FormSettings) *ScrapeError {
+	if settings == nil {
+		return nil
+	}
+	if !settings.AcceptingResponses {
+		return newScrapeError(ErrCodeFormClosed, "form is no longer accepting responses", nil)
+	}
+	if settings.RequiresSignIn {
+		return newScrapeError(ErrCodeSignInRequired, "form requires Google sign-in (verified email or one response per user)", nil)
+	}
+	return nil
+}
+
+// --- Payload Builder ---
+
+// injectRow: satu baris jawaban yang sudah dinormalisasi (Map [ID] -> [Jawaban]).
+type injectRow struct {
+	Index       int // posisi baris pada input answers
+	AnswersMap  map[int64]interface{}
+	Email       string
+	PageHistory string
+	Skipped     []int64          // entry ID jawaban di section yang tidak dilalui
+	Unknown     []string         // key jawaban yang tidak cocok dengan form
+	Other       map[int64]string // teks isian opsi "Other" per entry ID
+}
+
+// injectPayload: body formResponse satu baris + nilai per entry yang
+// dikirim (key "entry.<ID>", termasuk komponen tanggal/waktu & Other).
+type injectPayload struct {
+	Form            url.Values
+	PartialResponse []byte
+	Entries         map[string][]string
+	EntryIDs        []int64 // entry yang masuk partialResponse
+}
+
+// buildInjectPayload menyusun body formResponse satu baris. Urutan entry
+// di partialResponse mengikuti entry ID agar payload deterministik.
+func buildInjectPayload(saves FormSaveState, questionIndex map[int64]QuestionItem, rData injectRow, layouts []string) injectPayload {
+	var responses []interface{}
+	componentFields := url.Values{} // komponen tanggal/waktu
+	entries := make(map[string][]string)
+	var sentIDs []int64
+
+	ids := make([]int64, 0, len(rData.AnswersMap))
+	for entryID := range rData.AnswersMap {
+		ids = append(ids, entryID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// Build payload
+	for _, entryID := range ids {
+		val := rData.AnswersMap[entryID]
+		if val == nil {
+			continue
+		}
+
+		// FIX: Handling Slice/Array untuk Checkbox
+		// answerStrings dari form-validator.go
+		finalVal := answerStrings(val)
+
+		// Jika kosong, skip
+		if len(finalVal) == 0 {
+			continue
+		}
+
+		// Tanggal/waktu dikirim sebagai komponen terpisah (sudah lolos validasi)
+		if q := questionIndex[entryID]; q.Kind == KindDate || q.Kind == KindTime {
+			value, fields, err := encodeDateTimeAnswer(q, entryID, finalVal[0], layouts)
+			if err != nil {
+				continue
+			}
+			finalVal = []string{value}
+			for k, v := range fields {
+				componentFields[k] = v
+				entries[k] = v
+			}
+		}
+
+		// Struktur Entry Google Form: [nil, ID, [Values...], 0]
+		entryData := []interface{}{
+			nil,
+			entryID,
+			finalVal, // Harus array of string
+			0,
+		}
+		responses = append(responses, entryData)
+		entries[fmt.Sprintf("entry.%d", entryID)] = finalVal
+		sentIDs = append(sentIDs, entryID)
+	}
+
+	// Handle Email (jika ada form yang mewajibkan collect email)
+	var emailField interface{} = nil
+	if rData.Email != "" {
+		emailField = rData.Email
+	}
+
+	// Struktur Utama Payload
+	fullStructure := []interface{}{
+		responses,
+		emailField, // Index 1: Email Address (jika di-enable di form)
+		saves.Fbzx,
+	}
+
+	partialJSON, _ := json.Marshal(fullStructure)
+
+	data := url.Values{}
+	data.Set("fvv", "1")
+	data.Set("partialResponse", string(partialJSON))
+	data.Set("pageHistory", rData.PageHistory) // Path halaman hasil branching baris ini
+	data.Set("fbzx", saves.Fbzx)
+	data.Set("submissionTimestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	for k, v := range componentFields {
+		data[k] = v
+	}
+	for entryID, text := range rData.Other {
+		if _, sent := rData.AnswersMap[entryID]; sent {
+			key := fmt.Sprintf("entry.%d.other_option_response", entryID)
+			data.Set(key, text)
+			entries[key] = []string{text}
+		}
+	}
+
+	return injectPayload{Form: data, PartialResponse: partialJSON, Entries: entries, EntryIDs: sentIDs}
+}
+
+// snippetLimit: panjang maksimal potongan body response di hasil baris.
+const snippetLimit = 200
+
+// responseSnippet meringkas body response (whitespace dirapatkan).
+func responseSnippet(body []byte) string {
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if runes := []rune(text); len(runes) > snippetLimit {
+		text = string(runes[:snippetLimit])
+	}
+	return text
+}
+
+// --- Response Classification ---
+
+// Hasil submit menurut isi response. HTTP 200 saja belum berarti sukses:
+// Google bisa menjawab 200 dengan halaman form tertutup, halaman login,
+// atau form yang ditampilkan ulang karena pertanyaan wajib kosong.
+const (
+	SubmitOutcomeAccepted           = "accepted"
+	SubmitOutcomeRejectedValidation = "rejected-validation"
+	SubmitOutcomeFormClosed         = "form-closed"
+	SubmitOutcomeSignInRequired     = "sign-in-required"
+	SubmitOutcomeUnknown            = "unknown"
+)
+
+var (
+	// Penanda halaman konfirmasi "Your response has been recorded"
+	confirmationMarkers = []string{
+		"freebirdFormviewerViewResponseConfirmationMessage",
+		"Your response has been recorded",
+		"Tanggapan Anda telah direkam",
+		"Respons Anda telah direkam",
+	}
+	// Penanda pesan error per pertanyaan saat form ditampilkan ulang
+	validationMarkers = []string{
+		"freebirdFormviewerViewItemsItemErrorMessage",
+		"This is a required question",
+		"Pertanyaan ini wajib diisi",
+		"Ini adalah pertanyaan wajib",
+	}
+	signInMarkers = []string{
+		"accounts.google.com/ServiceLogin",
+		"accounts.google.com/v3/signin",
+		`data-sign-in-to-continue="true"`,
+	}
+)
+
+func containsAny(content string, markers []string) bool {
+	for _, m := range markers {
+		if m != "" && strings.Contains(content, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsConfirmation mencari pesan konfirmasi kustom di HTML. Pesan
+// dengan &, <, ' atau " tampil sebagai entity di halaman, jadi dicocokkan
+// mentah, versi html.EscapeString, dan terhadap body yang di-unescape.
+func containsConfirmation(body, confirmation string) bool {
+	if confirmation == "" {
+		return false
+	}
+	return strings.Contains(body, confirmation) ||
+		strings.Contains(body, html.EscapeString(confirmation)) ||
+		strings.Contains(html.UnescapeString(body), confirmation)
+}
+
+// classifySubmitResponse menentukan outcome dari URL akhir (setelah
+// redirect), HTTP status dan body response formResponse. confirmation =
+// pesan konfirmasi kustom form (Settings.ConfirmationMessage), boleh kosong.
+//
+// Urutan penting: redirect dulu (closedform / login), lalu pesan error
+// pertanyaan, baru konfirmasi. Penanda tutup/login di body hanya dipakai
+// jika tidak ada penanda konfirmasi, karena halaman konfirmasi asli bisa
+// memuat link login Google.
+func classifySubmitResponse(finalURL *url.URL, status int, body, confirmation string) (string, string) {
+	if finalURL != nil {
+		if strings.HasSuffix(finalURL.Path, "/closedform") {
+			return SubmitOutcomeFormClosed, "form is no longer accepting responses"
+		}
+		if finalURL.Host == "accounts.google.com" {
+			return SubmitOutcomeSignInRequired, "form redirects to Google sign-in"
+		}
+	}
+
+	// Form yang ditampilkan ulang memuat FB_PUBLIC_LOAD_DATA_, yang juga
+	// berisi pesan konfirmasi kustom; pesan kustom hanya dicocokkan di
+	// halaman tanpa data form.
+	formShown := strings.Contains(body, "FB_PUBLIC_LOAD_DATA_")
+	confirmed := containsAny(body, confirmationMarkers) ||
+		(!formShown && containsConfirmation(body, confirmation))
+
+	switch {
+	case containsAny(body, validationMarkers):
+		return SubmitOutcomeRejectedValidation, "form rejected the answers (required question or invalid value)"
+	case status == http.StatusOK && confirmed:
+		return SubmitOutcomeAccepted, ""
+	case !confirmed && looksClosed(body):
+		return SubmitOutcomeFormClosed, "form is no longer accepting responses"
+	case !confirmed && containsAny(body, signInMarkers):
+		return SubmitOutcomeSignInRequired, "form requires Google sign-in"
+	case status == http.StatusBadRequest:
+		return SubmitOutcomeRejectedValidation, "form rejected the payload (HTTP 400)"
+	case status == http.StatusOK && formShown:
+		// Form ditampilkan ulang tanpa konfirmasi = jawaban ditolak
+		return SubmitOutcomeRejectedValidation, "form was shown again instead of the confirmation page"
+	}
+	return SubmitOutcomeUnknown, fmt.Sprintf("HTTP %d without confirmation marker", status)
+}
+
+// submitInjectRow mengirim payload satu baris ke formResponse lalu
+// mencatat status, HTTP code, latensi, potongan body & outcome.
+func submitInjectRow(target FormRef, row injectRow, payload injectPayload, confirmation string) InjectRowResult {
+	res := newInjectRowResult(row, RowStatusFailed)
+	res.SentEntryIDs = payload.EntryIDs
+
+	postReq, err := http.NewRequest("POST", target.ResponseURL, strings.NewReader(payload.Form.Encode()))
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	postReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	postReq.Header.Set("User-Agent", "Mozilla/5.0 (DataFact Injector Bot)")
+
+	// Tambahkan Referer/Origin agar lebih dipercaya
+	postReq.Header.Set("Origin", formsOrigin())
+	postReq.Header.Set("Referer", target.ViewformURL)
+
+	start := time.Now()
+	resp, err := fastClient.Do(postReq)
+	if err != nil {
+		res.LatencyMs = time.Since(start).Milliseconds()
+		res.Error = err.Error()
+		return res
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	res.LatencyMs = time.Since(start).Milliseconds()
+	res.HTTPStatus = resp.StatusCode
+	res.Snippet = responseSnippet(body)
+
+	outcome, reason := classifySubmitResponse(resp.Request.URL, resp.StatusCode, string(body), confirmation)
+	res.Outcome = outcome
+	if outcome == SubmitOutcomeAccepted {
+		res.Status = RowStatusSuccess
+	} else {
+		res.Error = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, reason)
+	}
+	return res
+}
+
+// --- Injection Plan ---
+
+// injectPrepError: kegagalan sebelum ada baris yang dikirim + HTTP status.
+// Stale terisi jika saves tidak cocok dengan form live (409, body JSON).
+type injectPrepError struct {
+	Status  int
+	Message string
+	Stale   *StaleSavesResponse
+}
+
+func (e *injectPrepError) Error() string { return e.Message }
+
+func prepError(status int, message string) *injectPrepError {
+	return &injectPrepError{Status: status, Message: message}
+}
+
+func writeInjectPrepError(w http.ResponseWriter, e *injectPrepError) {
+	if e.Stale != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(e.Stale)
 		return
 	}
+	http.Error(w, e.Message, e.Status)
+}
 
+// injectPlan: request injector yang sudah di-decode, dinormalisasi dan
+// divalidasi. Baris valid siap dikirim lewat runInjection.
+type injectPlan struct {
+	req           InjectRequest
+	saves         FormSaveState
+	target        FormRef
+	questionIndex map[int64]QuestionItem
+
+	rows       []injectRow // baris valid yang akan dikirim
+	total      int         // semua baris input (termasuk invalid)
+	notes      []string    // catatan branching per baris
+	violations []RowViolations
+	invalid    []InjectRowResult // hasil baris yang gagal validasi
+}
+
+// prepareInjection menjalankan semua langkah sebelum submit: parsing saves
+// & answers, resolve target, cek pengaturan form, verify_schema, mapping
+// jawaban, branching dan validasi.
+func prepareInjection(req InjectRequest) (*injectPlan, *injectPrepError) {
 	// 2. Parsing Flexible 'Saves'
 	var savesData FormSaveState
 	if len(req.Saves) > 0 {
 		if err := parseFlexibleJSON(req.Saves, &savesData); err != nil {
-			http.Error(w, "invalid saves format: "+err.Error(), http.StatusBadRequest)
-			return
+			return nil, prepError(http.StatusBadRequest, "invalid saves format: "+err.Error())
 		}
 	}
 
@@ -70,23 +822,57 @@ func InjectorHandler(w http.ResponseWriter, r *http.Request) {
 	var rawAnswers []interface{}
 	if len(req.Answers) > 0 {
 		if err := parseFlexibleJSON(req.Answers, &rawAnswers); err != nil {
-			http.Error(w, "invalid answers format: "+err.Error(), http.StatusBadRequest)
-			return
+			return nil, prepError(http.StatusBadRequest, "invalid answers format: "+err.Error())
 		}
 	}
 
-	// 4. Normalisasi Jawaban (Map [ID] -> [Jawaban]) + Support Email
-	// Kita buat struktur struct sementara untuk menampung data baris
-	type RowData struct {
-		AnswersMap map[int64]interface{}
-		Email      string
+	if savesData.Source != "" {
+		return nil, prepError(http.StatusBadRequest, "saves imported from "+savesData.Source+" are generation-only and cannot be injected")
+	}
+
+	// dry_run tidak pernah menghubungi form
+	if req.DryRun && req.VerifySchema {
+		return nil, prepError(http.StatusBadRequest, "dry_run cannot be combined with verify_schema (dry_run never contacts the form)")
+	}
+
+	// Tentukan endpoint formResponse kanonik (bukan langsung req.FormURL)
+	target, err := resolveInjectTarget(req.FormURL, savesData.FormID, req.DryRun)
+	if err != nil {
+		se := asScrapeError(err)
+		return nil, prepError(se.Status(), "invalid form target: "+se.Error())
+	}
+
+	if se := checkFormSettings(savesData.Settings); se != nil {
+		return nil, prepError(se.Status(), "form cannot accept injected responses: "+se.Error())
+	}
+
+	if req.VerifySchema {
+		stale, err := checkSavesFresh(savesData, target, req.Refresh)
+		if err != nil {
+			status := http.StatusBadRequest
+			var se *ScrapeError
+			if errors.As(err, &se) {
+				status = se.Status()
+			}
+			return nil, prepError(status, "schema verification failed: "+err.Error())
+		}
+		if stale != nil {
+			return nil, &injectPrepError{Status: http.StatusConflict, Message: stale.Message, Stale: stale}
+		}
 	}
-	
-	var finalRows []RowData
 
-	for _, item := range rawAnswers {
+	questionIndex := indexQuestions(savesData)
+
+	// 4. Normalisasi Jawaban (Map [ID] -> [Jawaban]) + Support Email
+	// Baris yang tidak bisa dipetakan tetap dapat hasil invalid agar setiap
+	// baris input bisa direkonsiliasi.
+	var finalRows []injectRow
+	var unparsed []InjectRowResult
+
+	for rowIdx, item := range rawAnswers {
 		rowMap := make(map[int64]interface{})
 		var emailAddr string
+		var unknownKeys []string
 
 		switch v := item.(type) {
 		case []interface{}:
@@ -94,9 +880,14 @@ func InjectorHandler(w http.ResponseWriter, r *http.Request) {
 			for i, val := range v {
 				if i < len(savesData.EntryIDs) {
 					rowMap[savesData.EntryIDs[i]] = val
+				} else {
+					unknownKeys = append(unknownKeys, fmt.Sprintf("[%d]", i))
 				}
 			}
-			finalRows = append(finalRows, RowData{AnswersMap: rowMap})
+			var gridUnknown []string
+			rowMap, gridUnknown = expandGridAnswers(rowMap, questionIndex)
+			unknownKeys = append(unknownKeys, gridUnknown...)
+			finalRows = append(finalRows, injectRow{Index: rowIdx, AnswersMap: rowMap, Unknown: unknownKeys})
 
 		case map[string]interface{}:
 			// Object Mode
@@ -117,8 +908,8 @@ func InjectorHandler(w http.ResponseWriter, r *http.Request) {
 
 				// 2. Cek ID Manual
 				if idParsed, err := strconv.ParseInt(key, 10, 64); err == nil {
-					// Validasi keberadaan ID
-					isValid := false
+					// Validasi keberadaan ID (termasuk ID baris grid)
+					_, isValid := questionIndex[idParsed]
 					for _, eid := range savesData.EntryIDs {
 						if eid == idParsed {
 							isValid = true
@@ -127,161 +918,289 @@ func InjectorHandler(w http.ResponseWriter, r *http.Request) {
 					}
 					if isValid {
 						rowMap[idParsed] = val
+						continue
 					}
 				}
+
+				unknownKeys = append(unknownKeys, key)
 			}
-			
+			var gridUnknown []string
+			rowMap, gridUnknown = expandGridAnswers(rowMap, questionIndex)
+			unknownKeys = append(unknownKeys, gridUnknown...)
+
 			if len(rowMap) > 0 || emailAddr != "" {
-				finalRows = append(finalRows, RowData{AnswersMap: rowMap, Email: emailAddr})
+				finalRows = append(finalRows, injectRow{Index: rowIdx, AnswersMap: rowMap, Email: emailAddr, Unknown: unknownKeys})
+				continue
 			}
+			res := newInjectRowResult(injectRow{Index: rowIdx, Unknown: unknownKeys}, RowStatusInvalid)
+			res.Error = "no answer key matches a question in the form"
+			unparsed = append(unparsed, res)
 
 		default:
-			continue
+			res := newInjectRowResult(injectRow{Index: rowIdx}, RowStatusInvalid)
+			res.Error = fmt.Sprintf("row must be an object or array, got %T", item)
+			unparsed = append(unparsed, res)
 		}
 	}
 
 	if len(finalRows) == 0 {
-		http.Error(w, "no answers provided/parsed", http.StatusBadRequest)
-		return
+		return nil, prepError(http.StatusBadRequest, "no answers provided/parsed")
 	}
 
-	// 5. Proses Concurrent Injection
-	var wg sync.WaitGroup
-	total := len(finalRows)
-	resultChan := make(chan string, total)
+	// 4b. Hitung pageHistory per baris sesuai branching form.
+	// Saves lama (tanpa Pages) tetap memakai PageHistory statis.
+	// 4c. Validasi jawaban terhadap skema; baris dengan error tidak dikirim.
+	var branchNotes []string
+	var violations []RowViolations
+	rowResults := unparsed
+	validRows := finalRows[:0]
+	for _, row := range finalRows {
+		row.PageHistory = savesData.PageHistory
+		// Path dihitung dari nilai pilihan mentah & jawaban di section yang
+		// tidak dilalui dibuang dulu, baru teks Other dipisahkan.
+		var reached map[int64]bool
+		if len(savesData.Pages) > 0 {
+			path := resolvePagePath(savesData.Pages, row.AnswersMap, questionIndex)
+			reached = reachedEntries(savesData.Pages, path)
+			row.AnswersMap, row.Skipped = pruneUnreachedAnswers(row.AnswersMap, reached, questionIndex)
+			row.PageHistory = formatPageHistory(path)
+			if len(row.Skipped) > 0 {
+				branchNotes = append(branchNotes, fmt.Sprintf("Row %d: %d answer(s) on unreached sections skipped", row.Index, len(row.Skipped)))
+			}
+		}
+
+		var rowViolations []Violation
+		row.Other, rowViolations = extractOtherAnswers(row.AnswersMap, questionIndex)
+		if savesData.Settings != nil && savesData.Settings.EmailCollection == EmailCollectionInput && row.Email == "" {
+			rowViolations = append(rowViolations, Violation{
+				Key:     "email",
+				Level:   violationError,
+				Code:    "email_required",
+				Message: "form mengumpulkan email responden, isi key \"email\"",
+			})
+		}
+		for _, key := range row.Unknown {
+			rowViolations = append(rowViolations, Violation{
+				Key:     key,
+				Level:   violationWarning,
+				Code:    "unknown_key",
+				Message: "key tidak cocok dengan pertanyaan mana pun, diabaikan",
+			})
+		}
+		rowViolations = append(rowViolations, validateAnswers(row.AnswersMap, savesData.Questions, reached, req.DateLayouts)...)
+		if len(rowViolations) > 0 {
+			violations = append(violations, RowViolations{Row: row.Index, Violations: rowViolations})
+		}
+		if hasBlockingViolation(rowViolations) {
+			res := newInjectRowResult(row, RowStatusInvalid)
+			res.Error = "row failed validation, see violations"
+			rowResults = append(rowResults, res)
+			continue
+		}
+		validRows = append(validRows, row)
+	}
+
+	return &injectPlan{
+		req:           req,
+		saves:         savesData,
+		target:        target,
+		questionIndex: questionIndex,
+		rows:          validRows,
+		total:         len(rawAnswers),
+		notes:         branchNotes,
+		violations:    violations,
+		invalid:       rowResults,
+	}, nil
+}
 
-	successCount := 0
-	failCount := 0
+// dryRun membangun payload semua baris valid tanpa request keluar.
+func (p *injectPlan) dryRun() InjectResult {
+	rowResults := append([]InjectRowResult(nil), p.invalid...)
+	payloads := make([]DryRunPayload, 0, len(p.rows))
+	for _, row := range p.rows {
+		payload := buildInjectPayload(p.saves, p.questionIndex, row, p.req.DateLayouts)
+		payloads = append(payloads, DryRunPayload{
+			Row:             row.Index,
+			ResponseURL:     p.target.ResponseURL,
+			Body:            payload.Form.Encode(),
+			PartialResponse: payload.PartialResponse,
+			PageHistory:     row.PageHistory,
+			Fbzx:            p.saves.Fbzx,
+			Entries:         payload.Entries,
+		})
+		res := newInjectRowResult(row, RowStatusDryRun)
+		res.SentEntryIDs = payload.EntryIDs
+		rowResults = append(rowResults, res)
+	}
+	sortRowResults(rowResults)
+	return InjectResult{
+		Total:   p.total,
+		Invalid: len(p.invalid),
+		Details: p.notes,
+
+		Rows:       rowResults,
+		Violations: p.violations,
+		DryRun:     true,
+		Payloads:   payloads,
+	}
+}
+
+// runInjection mengirim semua baris valid (maks 10 concurrent). onRow
+// (boleh nil) dipanggil serial setiap satu baris selesai. Jika ctx
+// dibatalkan, baris yang belum mulai dikirim ditandai canceled; request
+// yang sedang berjalan tetap diselesaikan.
+func runInjection(ctx context.Context, p *injectPlan, onRow func(InjectRowResult)) InjectResult {
+	var confirmation string
+	if p.saves.Settings != nil {
+		confirmation = p.saves.Settings.ConfirmationMessage
+	}
+	var wg sync.WaitGroup
 	var mu sync.Mutex
+	sent := make([]InjectRowResult, len(p.rows))
 
 	maxConcurrency := 10 // Jangan terlalu agresif ke Google
 	semaphore := make(chan struct{}, maxConcurrency)
 
-	for i, row := range finalRows {
+	for i, row := range p.rows {
 		wg.Add(1)
 
-		go func(idx int, rData RowData) {
+		go func(i int, rData injectRow) {
 			defer wg.Done()
 
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+			var res InjectRowResult
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+				if ctx.Err() != nil {
+					res = newInjectRowResult(rData, RowStatusCanceled)
+					break
+				}
+				payload := buildInjectPayload(p.saves, p.questionIndex, rData, p.req.DateLayouts)
+				res = submitInjectRow(p.target, rData, payload, confirmation)
+			case <-ctx.Done():
+				res = newInjectRowResult(rData, RowStatusCanceled)
+			}
+
+			mu.Lock()
+			sent[i] = res
+			if onRow != nil {
+				onRow(res)
+			}
+			mu.Unlock()
+		}(i, row)
+	}
 
-			var responses []interface{}
+	wg.Wait()
 
-			// Build payload
-			for entryID, val := range rData.AnswersMap {
-				if val == nil {
-					continue
-				}
+	rowResults := append(append([]InjectRowResult(nil), p.invalid...), sent...)
+	sortRowResults(rowResults)
 
-				// FIX: Handling Slice/Array untuk Checkbox
-				var finalVal []string
+	result := InjectResult{
+		Total:   p.total,
+		Invalid: len(p.invalid),
 
-				switch rawVal := val.(type) {
-				case []interface{}:
-					// Jika input JSON adalah array: ["A", "B"]
-					for _, subVal := range rawVal {
-						finalVal = append(finalVal, fmt.Sprintf("%v", subVal))
-					}
-				case []string:
-					finalVal = rawVal
-				default:
-					// Single value
-					finalVal = []string{fmt.Sprintf("%v", val)}
-				}
-                
-                // Jika kosong, skip
-                if len(finalVal) == 0 {
-                    continue
-                }
-
-				// Struktur Entry Google Form: [nil, ID, [Values...], 0]
-				entryData := []interface{}{
-					nil,
-					entryID,
-					finalVal, // Harus array of string
-					0,
-				}
-				responses = append(responses, entryData)
-			}
-            
-            // Handle Email (jika ada form yang mewajibkan collect email)
-            var emailField interface{} = nil
-            if rData.Email != "" {
-                emailField = rData.Email
-            }
-
-			// Struktur Utama Payload
-			fullStructure := []interface{}{
-				responses,
-				emailField, // Index 1: Email Address (jika di-enable di form)
-				savesData.Fbzx,
+		Rows:       rowResults,
+		Outcomes:   make(map[string]int),
+		Violations: p.violations,
+	}
+	details := p.notes
+	for _, res := range sent {
+		if res.Outcome != "" {
+			result.Outcomes[res.Outcome]++
+		}
+		switch res.Status {
+		case RowStatusSuccess:
+			result.Success++
+		case RowStatusCanceled:
+			result.Canceled++
+		default:
+			result.Failed++
+		}
+	}
+	for _, res := range rowResults {
+		if res.Status == RowStatusFailed {
+			errMsg := res.Error
+			if res.Snippet != "" {
+				errMsg += " | Body: " + res.Snippet
 			}
+			details = append(details, fmt.Sprintf("Row %d failed: %s", res.Row, errMsg))
+		}
+	}
+	result.Details = details
+	return result
+}
 
-			partialJSON, _ := json.Marshal(fullStructure)
-
-			data := url.Values{}
-			data.Set("fvv", "1")
-			data.Set("partialResponse", string(partialJSON))
-			data.Set("pageHistory", savesData.PageHistory) // Menggunakan hasil dinamis dari scrapper
-			data.Set("fbzx", savesData.Fbzx)
-			data.Set("submissionTimestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+// --- Handler ---
 
-			postReq, _ := http.NewRequest("POST", req.FormURL, strings.NewReader(data.Encode()))
-			postReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-			postReq.Header.Set("User-Agent", "Mozilla/5.0 (DataFact Injector Bot)")
-			
-			// Tambahkan Referer/Origin agar lebih dipercaya
-			postReq.Header.Set("Origin", "https://docs.google.com")
-			postReq.Header.Set("Referer", req.FormURL)
+func InjectorHandler(w http.ResponseWriter, r *http.Request) {
+	if err := mustAuthorize(r); err != nil {
+		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
 
-			resp, err := fastClient.Do(postReq)
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
 
-			mu.Lock()
-			if err == nil && resp.StatusCode == 200 {
-				successCount++
-                // Optional: Debug success
-                // resultChan <- fmt.Sprintf("Row %d success", idx)
-			} else {
-				failCount++
-				errMsg := "unknown error"
-				if err != nil {
-					errMsg = err.Error()
-				} else {
-                    // Baca body error google untuk detail
-                    bodyErr, _ := io.ReadAll(resp.Body)
-                    // Ambil potongan body untuk log (kadang HTML panjang)
-                    snippet := string(bodyErr)
-                    if len(snippet) > 200 {
-                        snippet = snippet[:200]
-                    }
-					errMsg = fmt.Sprintf("HTTP %d | Body: %s", resp.StatusCode, snippet)
-				}
-				resultChan <- fmt.Sprintf("Row %d failed: %s", idx, errMsg)
-			}
-			mu.Unlock()
+	// 1. Decode Wrapper
+	var req InjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
-			if resp != nil {
-				io.Copy(io.Discard, resp.Body)
-				resp.Body.Close()
-			}
+	mode, ok := streamMode(req.Stream, r)
+	if !ok {
+		http.Error(w, "stream must be ndjson or sse", http.StatusBadRequest)
+		return
+	}
 
-		}(i, row)
+	// 2-4. Normalisasi & validasi
+	plan, perr := prepareInjection(req)
+	if perr != nil {
+		writeInjectPrepError(w, perr)
+		return
 	}
 
-	wg.Wait()
-	close(resultChan)
+	if mode != "" {
+		streamInjection(w, r, mode, plan)
+		return
+	}
 
-	var details []string
-	for msg := range resultChan {
-		details = append(details, msg)
+	// 4d. Dry run: kembalikan payload tanpa request keluar
+	// 5. Proses Concurrent Injection (berhenti jika client memutus koneksi)
+	var result InjectResult
+	if req.DryRun {
+		result = plan.dryRun()
+	} else {
+		result = runInjection(r.Context(), plan, nil)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(InjectResult{
-		Total:   total,
-		Success: successCount,
-		Failed:  failCount,
-		Details: details,
-	})
-}
\ No newline at end of file
+	json.NewEncoder(w).Encode(result)
+}
+
+// streamInjection: sama seperti InjectorHandler tapi hasil tiap baris
+// dikirim begitu selesai. Baris invalid dikirim lebih dulu.
+func streamInjection(w http.ResponseWriter, r *http.Request, mode string, plan *injectPlan) {
+	stream := newProgressStream(w, mode)
+	for _, res := range plan.invalid {
+		stream.send("row", res)
+	}
+
+	var result InjectResult
+	if plan.req.DryRun {
+		result = plan.dryRun()
+		for _, res := range result.Rows {
+			if res.Status == RowStatusDryRun {
+				stream.send("row", res)
+			}
+		}
+	} else {
+		result = runInjection(r.Context(), plan, func(res InjectRowResult) {
+			stream.send("row", res)
+		})
+	}
+	stream.send("summary", result)
+}
@@ -0,0 +1,607 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"datafact/internal/fakeform"
+)
+
+func TestClassifySubmitResponse(t *testing.T) {
+	formURL, _ := url.Parse("https://docs.google.com/forms/d/e/abc/formResponse")
+	closedURL, _ := url.Parse("https://docs.google.com/forms/d/e/abc/closedform")
+	loginURL, _ := url.Parse("https://accounts.google.com/v3/signin/identifier")
+
+	const custom = "Terima kasih sudah mengisi survei"
+	redisplayed := `<div class="freebirdFormviewerViewItemsItemErrorMessage">This is a required question</div>` +
+		`<script>var FB_PUBLIC_LOAD_DATA_ = [null,["d",[],["` + custom + `"]]];</script>`
+
+	cases := []struct {
+		name   string
+		final  *url.URL
+		status int
+		body   string
+		want   string
+	}{
+		{"confirmation marker", formURL, http.StatusOK, `<div class="freebirdFormviewerViewResponseConfirmationMessage">Your response has been recorded.</div>`, SubmitOutcomeAccepted},
+		{"custom confirmation", formURL, http.StatusOK, `<div>` + custom + `</div>`, SubmitOutcomeAccepted},
+		{"confirmation with login link", formURL, http.StatusOK, `<div>Your response has been recorded.</div><a href="https://accounts.google.com/ServiceLogin?continue=x">Switch account</a>`, SubmitOutcomeAccepted},
+		{"redisplayed form with custom confirmation in load data", formURL, http.StatusOK, redisplayed, SubmitOutcomeRejectedValidation},
+		{"redisplayed form without error marker", formURL, http.StatusOK, `<script>var FB_PUBLIC_LOAD_DATA_ = [null,["` + custom + `"]];</script>`, SubmitOutcomeRejectedValidation},
+		{"closed redirect", closedURL, http.StatusOK, `<div class="freebirdFormviewerViewResponseConfirmationMessage">This form is no longer accepting responses</div>`, SubmitOutcomeFormClosed},
+		{"closed body", formURL, http.StatusOK, `<div>This form is no longer accepting responses</div>`, SubmitOutcomeFormClosed},
+		{"login redirect", loginURL, http.StatusOK, `<html>Sign in</html>`, SubmitOutcomeSignInRequired},
+		{"login body", formURL, http.StatusOK, `<a href="https://accounts.google.com/ServiceLogin">Sign in</a>`, SubmitOutcomeSignInRequired},
+		{"bad request", formURL, http.StatusBadRequest, `<title>Error 400 (Bad Request)</title>`, SubmitOutcomeRejectedValidation},
+		{"unknown", formURL, http.StatusOK, `<html>ok</html>`, SubmitOutcomeUnknown},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := classifySubmitResponse(tc.final, tc.status, tc.body, custom)
+			if got != tc.want {
+				t.Errorf("outcome = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	// Pesan dengan karakter khusus tampil ter-escape di HTML
+	const special = `Thanks & "see you" soon`
+	escaped := []string{
+		`<div>Thanks &amp; &#34;see you&#34; soon</div>`,
+		`<div>Thanks &amp; &quot;see you&quot; soon</div>`,
+	}
+	for _, body := range escaped {
+		if got, _ := classifySubmitResponse(formURL, http.StatusOK, body, special); got != SubmitOutcomeAccepted {
+			t.Errorf("escaped confirmation %s: outcome = %q, want accepted", body, got)
+		}
+	}
+}
+
+func TestPrepareInjectionReportsEveryInputRow(t *testing.T) {
+	saves := `{"form_id":"abc","fbzx":"1","entry_ids":[11],"entry_mappings":{"Nama":11},
+		"questions":[{"id":11,"text":"Nama","kind":"short_answer"}]}`
+	req := InjectRequest{
+		Saves:   json.RawMessage(saves),
+		Answers: json.RawMessage(`[{"Nama":"A"},{"bogus":"x"},42,{"Nama":"B"}]`),
+	}
+	plan, perr := prepareInjection(req)
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	result := plan.dryRun()
+	if result.Total != 4 {
+		t.Errorf("total = %d, want 4", result.Total)
+	}
+	want := []string{RowStatusDryRun, RowStatusInvalid, RowStatusInvalid, RowStatusDryRun}
+	if len(result.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(result.Rows), len(want))
+	}
+	for i, row := range result.Rows {
+		if row.Row != i || row.Status != want[i] {
+			t.Errorf("rows[%d] = {row %d, %s}, want {row %d, %s}", i, row.Row, row.Status, i, want[i])
+		}
+	}
+	if got := result.Rows[1].DroppedKeys; len(got) != 1 || got[0] != "bogus" {
+		t.Errorf("rows[1].dropped_keys = %v, want [bogus]", got)
+	}
+}
+
+func TestPrepareInjectionExpandsGridAnswers(t *testing.T) {
+	saves := `{"form_id":"abc","fbzx":"1","entry_ids":[2008,2009,2010,2011],
+		"entry_mappings":{"Penilaian layanan":2008,"Fitur yang dipakai":2010},
+		"questions":[
+			{"id":2008,"text":"Penilaian layanan","kind":"grid","options":["Buruk","Cukup","Baik"],
+			 "rows":[{"id":2008,"label":"Kecepatan"},{"id":2009,"label":"Keramahan"}]},
+			{"id":2010,"text":"Fitur yang dipakai","kind":"checkbox_grid","options":["Web","Mobile"],
+			 "rows":[{"id":2010,"label":"Minggu ini"},{"id":2011,"label":"Bulan lalu"}]}]}`
+	req := InjectRequest{
+		Saves: json.RawMessage(saves),
+		Answers: json.RawMessage(`[{
+			"Penilaian layanan": {"Kecepatan": "Baik", "Keramahan": "Cukup"},
+			"Fitur yang dipakai": {"Minggu ini": ["Web", "Mobile"], "Besok": ["Web"]}
+		}]`),
+	}
+	plan, perr := prepareInjection(req)
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	result := plan.dryRun()
+	if len(result.Payloads) != 1 {
+		t.Fatalf("rows = %+v, want one payload", result.Rows)
+	}
+	entries := result.Payloads[0].Entries
+	want := map[string][]string{
+		"entry.2008": {"Baik"},
+		"entry.2009": {"Cukup"},
+		"entry.2010": {"Web", "Mobile"},
+	}
+	for key, vals := range want {
+		if !reflect.DeepEqual(entries[key], vals) {
+			t.Errorf("%s = %v, want %v", key, entries[key], vals)
+		}
+	}
+	if _, ok := entries["entry.2011"]; ok {
+		t.Errorf("unanswered grid row was sent: %v", entries["entry.2011"])
+	}
+	if got := result.Rows[0].DroppedKeys; !reflect.DeepEqual(got, []string{"Fitur yang dipakai [Besok]"}) {
+		t.Errorf("dropped_keys = %v, want unknown grid row", got)
+	}
+}
+
+func TestPrepareInjectionChecksFormSettings(t *testing.T) {
+	cases := []struct {
+		settings string
+		status   int
+	}{
+		{`{"accepting_responses":false}`, http.StatusGone},
+		{`{"accepting_responses":true,"requires_sign_in":true}`, http.StatusForbidden},
+		{`{"accepting_responses":true,"email_collection":"responder_input"}`, 0},
+	}
+	for _, c := range cases {
+		saves := `{"form_id":"abc","fbzx":"1","entry_ids":[11],"settings":` + c.settings + `,
+			"questions":[{"id":11,"text":"Nama","kind":"short_answer"}]}`
+		_, perr := prepareInjection(InjectRequest{Saves: json.RawMessage(saves), Answers: json.RawMessage(`[{"Nama":"A","email":"a@example.com"}]`)})
+		status := 0
+		if perr != nil {
+			status = perr.Status
+		}
+		if status != c.status {
+			t.Errorf("settings %s: status = %d (%v), want %d", c.settings, status, perr, c.status)
+		}
+	}
+}
+
+// startFakeForms menjalankan fakeform di httptest lalu mengarahkan
+// googleFormsBase dan cache skema ke server itu selama test.
+func startFakeForms(t *testing.T, forms ...fakeform.Form) *fakeform.Server {
+	t.Helper()
+	fake := fakeform.New()
+	for _, f := range forms {
+		if err := fake.AddForm(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(fake)
+	prevBase, prevCache := googleFormsBase, formSchemaCache
+	googleFormsBase = srv.URL + "/forms"
+	formSchemaCache = newSchemaCache(time.Hour, 16)
+	t.Cleanup(func() {
+		srv.Close()
+		googleFormsBase, formSchemaCache = prevBase, prevCache
+	})
+	return fake
+}
+
+func TestCheckSavesFreshIgnoresFreshCache(t *testing.T) {
+	v1 := `[null,[null,[[1,"Nama",null,0,[[11,null,1]]]]],null,null,null,null,null,null,"Form"]`
+	v2 := `[null,[null,[[1,"Nama",null,0,[[11,null,1]]],[2,"Kota",null,0,[[12,null,0]]]]],null,null,null,null,null,null,"Form"]`
+	fake := startFakeForms(t, fakeform.Form{ID: "fresh", LoadData: json.RawMessage(v1), Fbzx: "1"})
+
+	scraped, err := scrapeGoogleForm(googleFormsBase+"/d/e/fresh/viewform", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, _ := resolveFormURL(googleFormsBase + "/d/e/fresh/viewform")
+	if stale, err := checkSavesFresh(scraped.Saves, target, false); err != nil || stale != nil {
+		t.Fatalf("unchanged form: stale = %+v, err = %v", stale, err)
+	}
+
+	// Form diedit saat entry cache masih fresh
+	if err := fake.AddForm(fakeform.Form{ID: "fresh", LoadData: json.RawMessage(v2), Fbzx: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := checkSavesFresh(scraped.Saves, target, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale == nil {
+		t.Fatal("edited form not detected while cache entry was fresh")
+	}
+	if len(stale.Diff.Added) != 1 {
+		t.Errorf("diff added = %+v, want 1 question", stale.Diff.Added)
+	}
+}
+
+func TestPrepareInjectionRejectsMultipleOtherTexts(t *testing.T) {
+	saves := `{"form_id":"abc","fbzx":"1","entry_ids":[11],"entry_mappings":{"Hobi":11},
+		"questions":[{"id":11,"text":"Hobi","kind":"checkbox","options":["Membaca","Musik"],"has_other":true}]}`
+	req := InjectRequest{
+		Saves:   json.RawMessage(saves),
+		Answers: json.RawMessage(`[{"Hobi":["Membaca","Mancing"]},{"Hobi":["Membaca","Mancing",{"other":"Masak"}]}]`),
+	}
+	plan, perr := prepareInjection(req)
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	result := plan.dryRun()
+	if len(result.Rows) != 2 || result.Rows[0].Status != RowStatusDryRun || result.Rows[1].Status != RowStatusInvalid {
+		t.Fatalf("rows = %+v, want [dry_run invalid]", result.Rows)
+	}
+	if got := result.Payloads[0].Entries["entry.11.other_option_response"]; len(got) != 1 || got[0] != "Mancing" {
+		t.Errorf("other text = %v, want [Mancing]", got)
+	}
+
+	found := false
+	for _, rv := range result.Violations {
+		for _, v := range rv.Violations {
+			if rv.Row == 1 && v.Code == "multiple_other" && v.EntryID == 11 {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("violations = %+v, want multiple_other on row 1", result.Violations)
+	}
+}
+
+// TestInjectorRoundTripWithFakeForm: scrape form dari fakeform, inject
+// lewat InjectorHandler, lalu cek kiriman yang diterima server.
+func TestInjectorRoundTripWithFakeForm(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "forms", "sections-branching.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`(?s)FB_PUBLIC_LOAD_DATA_ = (.*?);</script>`).FindSubmatch(page)
+	if m == nil {
+		t.Fatal("fixture has no FB_PUBLIC_LOAD_DATA_")
+	}
+	fake := startFakeForms(t, fakeform.Form{ID: "branching", LoadData: m[1], Fbzx: "tok-1", CollectEmail: true})
+	t.Setenv("DATAFACT_API_KEY", "test-key")
+
+	scraped, err := scrapeGoogleForm(googleFormsBase+"/d/e/branching/viewform", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saves, _ := json.Marshal(scraped.Saves)
+	body, _ := json.Marshal(InjectRequest{
+		Saves: saves,
+		Answers: json.RawMessage(`[
+			{"email":"a@example.com","Pernah belanja online?":"Ya","Platform favorit":"Shopee","Saran":"Ongkir murah"},
+			{"email":"b@example.com","Pernah belanja online?":"Tidak","Platform favorit":"Shopee"}
+		]`),
+		VerifySchema: true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/form-injector", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	rec := httptest.NewRecorder()
+	InjectorHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var result InjectResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Success != 2 {
+		t.Fatalf("success = %d, want 2 (rows %+v)", result.Success, result.Rows)
+	}
+
+	subs := fake.Submissions("branching")
+	if len(subs) != 2 {
+		t.Fatalf("server got %d submissions, want 2", len(subs))
+	}
+	byEmail := map[string]fakeform.Submission{}
+	for _, s := range subs {
+		if !s.Accepted {
+			t.Errorf("submission rejected: %v", s.Errors)
+		}
+		byEmail[s.Email] = s
+	}
+
+	buyer := byEmail["a@example.com"]
+	if !reflect.DeepEqual(buyer.PageHistory, []int{0, 1, 3}) {
+		t.Errorf("buyer page history = %v, want [0 1 3]", buyer.PageHistory)
+	}
+	if got := buyer.Answers["4002"]; len(got) != 1 || got[0] != "Shopee" {
+		t.Errorf("buyer entry 4002 = %v, want [Shopee]", got)
+	}
+	// "Tidak" langsung submit: jawaban section lain tidak ikut dikirim
+	other := byEmail["b@example.com"]
+	if !reflect.DeepEqual(other.PageHistory, []int{0}) {
+		t.Errorf("non-buyer page history = %v, want [0]", other.PageHistory)
+	}
+	if _, sent := other.Answers["4002"]; sent {
+		t.Errorf("non-buyer sent entry 4002 from an unreached section")
+	}
+}
+
+func TestPrepareInjectionBranchesOnOtherChoice(t *testing.T) {
+	// Opsi Other langsung submit; section 500 hanya untuk "Ya"
+	saves := `{"form_id":"abc","fbzx":"1","entry_ids":[11,12,13],
+		"entry_mappings":{"Belanja":11,"Nama toko":12,"Hobi":13},
+		"questions":[
+			{"id":11,"text":"Belanja","kind":"multiple_choice","options":["Ya"],"has_other":true,"required":true,
+			 "go_to":{"Ya":500,"__other_option__":-3}},
+			{"id":12,"text":"Nama toko","kind":"short_answer","required":true},
+			{"id":13,"text":"Hobi","kind":"checkbox","options":["Membaca"],"has_other":true}],
+		"pages":[{"index":0,"title":"A","entry_ids":[11]},{"index":1,"id":500,"title":"B","entry_ids":[12,13]}]}`
+	req := InjectRequest{
+		Saves: json.RawMessage(saves),
+		Answers: json.RawMessage(`[
+			{"Belanja":"Kadang-kadang"},
+			{"Belanja":{"other":"Jarang"},"Hobi":["Mancing","Masak"]},
+			{"Belanja":"Ya","Nama toko":"Toko A"}
+		]`),
+	}
+	plan, perr := prepareInjection(req)
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	result := plan.dryRun()
+	for i, row := range result.Rows {
+		if row.Status != RowStatusDryRun {
+			t.Errorf("rows[%d] status = %s (%s), violations %+v", i, row.Status, row.Error, result.Violations)
+		}
+	}
+	if len(result.Payloads) != 3 {
+		t.Fatalf("got %d payloads, want 3", len(result.Payloads))
+	}
+
+	wantHistory := []string{"0", "0", "0,1"}
+	for i, p := range result.Payloads {
+		if p.PageHistory != wantHistory[i] {
+			t.Errorf("payload %d page history = %q, want %q", i, p.PageHistory, wantHistory[i])
+		}
+	}
+	if got := result.Payloads[0].Entries["entry.11.other_option_response"]; len(got) != 1 || got[0] != "Kadang-kadang" {
+		t.Errorf("other text = %v, want [Kadang-kadang]", got)
+	}
+	// Dua teks Other di section yang tidak dilalui hanya di-skip
+	if got := result.Rows[1].SkippedEntryIDs; !reflect.DeepEqual(got, []int64{13}) {
+		t.Errorf("rows[1].skipped_entry_ids = %v, want [13]", got)
+	}
+}
+
+func TestResolveInjectTarget(t *testing.T) {
+	const id = "1FAIpQLSdTargetFormID"
+	prefilled := googleFormsBase + "/d/e/" + id + "/viewform?usp=pp_url&entry.11=A"
+
+	ref, err := resolveInjectTarget(prefilled, id, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.ResponseURL != googleFormsBase+"/d/e/"+id+"/formResponse" {
+		t.Errorf("response url = %s, want canonical formResponse", ref.ResponseURL)
+	}
+	if ref, err := resolveInjectTarget("", id, false); err != nil || ref.ID != id {
+		t.Errorf("empty form_url: ref = %+v, err = %v, want saves form_id", ref, err)
+	}
+	// Saves lama (form_id scraped_<unix>) hanya bisa lewat form_url
+	if ref, err := resolveInjectTarget(prefilled, "scraped_1700000000", false); err != nil || ref.ID != id {
+		t.Errorf("legacy saves: ref = %+v, err = %v", ref, err)
+	}
+
+	bad := []struct{ formURL, savedID string }{
+		{googleFormsBase + "/d/e/1FAIpQLSdOtherFormID/viewform", id},
+		{"", "scraped_1700000000"},
+		{"https://example.com/form", id},
+	}
+	for _, c := range bad {
+		if _, err := resolveInjectTarget(c.formURL, c.savedID, false); err == nil {
+			t.Errorf("form_url %q with saves %q: expected error", c.formURL, c.savedID)
+		} else if se := asScrapeError(err); se.Status() != http.StatusBadRequest {
+			t.Errorf("form_url %q: status = %d, want 400", c.formURL, se.Status())
+		}
+	}
+}
+
+func TestPrepareInjectionDryRunStaysOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry_run made a request to %s", r.URL)
+	}))
+	defer srv.Close()
+	oldBase := googleFormsBase
+	googleFormsBase = srv.URL + "/forms"
+	defer func() { googleFormsBase = oldBase }()
+
+	saves := `{"form_id":"1FAIpQLSdry","fbzx":"1","entry_ids":[11],"entry_mappings":{"Nama":11},
+		"questions":[{"id":11,"text":"Nama","kind":"short_answer"}]}`
+	answers := json.RawMessage(`[{"Nama":"A"}]`)
+
+	// Link edit dan forms.gle tidak dibuka; target diambil dari saves
+	for _, link := range []string{srv.URL + "/forms/d/editID/edit", "https://forms.gle/abc"} {
+		plan, perr := prepareInjection(InjectRequest{FormURL: link, Saves: json.RawMessage(saves), Answers: answers, DryRun: true})
+		if perr != nil {
+			t.Fatalf("%s: %v", link, perr)
+		}
+		if plan.target.ID != "1FAIpQLSdry" {
+			t.Errorf("%s: target = %s, want saves form_id", link, plan.target.ID)
+		}
+	}
+
+	legacy := `{"form_id":"scraped_1","fbzx":"1","entry_ids":[11],"questions":[{"id":11,"text":"Nama","kind":"short_answer"}]}`
+	_, perr := prepareInjection(InjectRequest{FormURL: "https://forms.gle/abc", Saves: json.RawMessage(legacy), Answers: answers, DryRun: true})
+	if perr == nil || perr.Status != http.StatusBadRequest {
+		t.Errorf("legacy saves with short link: err = %v, want 400", perr)
+	}
+
+	_, perr = prepareInjection(InjectRequest{Saves: json.RawMessage(saves), Answers: answers, DryRun: true, VerifySchema: true})
+	if perr == nil || perr.Status != http.StatusBadRequest {
+		t.Errorf("dry_run + verify_schema: err = %v, want 400", perr)
+	}
+}
+
+func TestEncodeDateTimeAnswer(t *testing.T) {
+	date := QuestionItem{Kind: KindDate, Date: &DateMeta{IncludeYear: true}}
+	dateTime := QuestionItem{Kind: KindDate, Date: &DateMeta{IncludeYear: true, IncludeTime: true}}
+	noYear := QuestionItem{Kind: KindDate, Date: &DateMeta{}}
+	clock := QuestionItem{Kind: KindTime, Time: &TimeMeta{}}
+	duration := QuestionItem{Kind: KindTime, Time: &TimeMeta{Duration: true}}
+
+	cases := []struct {
+		name    string
+		q       QuestionItem
+		raw     string
+		layouts []string
+		value   string
+		fields  map[string]string
+	}{
+		{"date", date, "2024-03-07", nil, "2024-03-07",
+			map[string]string{"year": "2024", "month": "03", "day": "07"}},
+		{"date from RFC3339", date, "2024-03-07T10:30:00+07:00", nil, "2024-03-07",
+			map[string]string{"year": "2024", "month": "03", "day": "07"}},
+		{"date with time", dateTime, "2024-03-07T09:05", nil, "2024-03-07 09:05",
+			map[string]string{"year": "2024", "month": "03", "day": "07", "hour": "09", "minute": "05"}},
+		{"date without year", noYear, "2024-12-25", nil, "12-25",
+			map[string]string{"month": "12", "day": "25"}},
+		{"custom layout", date, "07/03/2024", []string{"02/01/2006"}, "2024-03-07",
+			map[string]string{"year": "2024", "month": "03", "day": "07"}},
+		{"time", clock, "7:45", []string{"15:04", "3:04"}, "07:45",
+			map[string]string{"hour": "07", "minute": "45"}},
+		{"time from datetime", clock, "2024-03-07 18:20", nil, "18:20",
+			map[string]string{"hour": "18", "minute": "20"}},
+		{"duration", duration, "26:05:09", nil, "26:05:09",
+			map[string]string{"hour": "26", "minute": "05", "second": "09"}},
+		{"go duration", duration, "1h30m", nil, "01:30:00",
+			map[string]string{"hour": "01", "minute": "30", "second": "00"}},
+	}
+	for _, c := range cases {
+		value, fields, err := encodeDateTimeAnswer(c.q, 42, c.raw, c.layouts)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		want := url.Values{}
+		for part, v := range c.fields {
+			want.Set("entry.42_"+part, v)
+		}
+		if value != c.value || !reflect.DeepEqual(fields, want) {
+			t.Errorf("%s: got %q %v, want %q %v", c.name, value, fields, c.value, want)
+		}
+	}
+
+	for _, bad := range []struct {
+		q   QuestionItem
+		raw string
+	}{{date, "07-03-2024"}, {date, "kemarin"}, {clock, "25:00"}, {duration, "1:75:00"}} {
+		if _, _, err := encodeDateTimeAnswer(bad.q, 42, bad.raw, nil); err == nil {
+			t.Errorf("%s %q: expected error", bad.q.Kind, bad.raw)
+		}
+	}
+}
+
+func TestResolvePagePath(t *testing.T) {
+	questions := map[int64]QuestionItem{
+		11: {ID: 11, Kind: KindMultipleChoice, Options: []string{"Ya", "Tidak"}, GoTo: map[string]int64{"Ya": 500, "Tidak": 700}},
+		12: {ID: 12, Kind: KindShortAnswer},
+		13: {ID: 13, Kind: KindShortAnswer},
+		14: {ID: 14, Kind: KindShortAnswer},
+	}
+	pages := []FormPage{
+		{Index: 0, EntryIDs: []int64{11}},
+		{Index: 1, ID: 500, EntryIDs: []int64{12}, GoTo: 700},
+		{Index: 2, ID: 600, EntryIDs: []int64{13}},
+		{Index: 3, ID: 700, EntryIDs: []int64{14}},
+	}
+	cases := []struct {
+		name    string
+		answers map[int64]interface{}
+		want    []int
+	}{
+		{"option jumps then page jumps", map[int64]interface{}{11: "Ya"}, []int{0, 1, 3}},
+		{"option skips sections", map[int64]interface{}{11: "Tidak"}, []int{0, 3}},
+		{"unanswered follows order", map[int64]interface{}{}, []int{0, 1, 3}},
+	}
+	for _, c := range cases {
+		if got := resolvePagePath(pages, c.answers, questions); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: path = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// Jawaban di section yang tidak dilalui dibuang
+	answers := map[int64]interface{}{11: "Tidak", 12: "x", 13: "y", 14: "z"}
+	reached := reachedEntries(pages, resolvePagePath(pages, answers, questions))
+	kept, skipped := pruneUnreachedAnswers(answers, reached, questions)
+	if !reflect.DeepEqual(skipped, []int64{12, 13}) || len(kept) != 2 {
+		t.Errorf("kept = %v, skipped = %v, want skipped [12 13]", kept, skipped)
+	}
+	if got := formatPageHistory([]int{0, 3}); got != "0,3" {
+		t.Errorf("page history = %q, want 0,3", got)
+	}
+
+	// Navigasi yang melompat mundur tidak boleh loop selamanya
+	loop := []FormPage{{Index: 0, GoTo: 500}, {Index: 1, ID: 500, GoTo: 500}}
+	if got := resolvePagePath(loop, nil, questions); len(got) > 2*len(loop)+1 {
+		t.Errorf("looping form path = %v", got)
+	}
+}
+
+func TestInjectorHandlerDryRunReturnsPayloads(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry_run made a request to %s", r.URL)
+	}))
+	defer srv.Close()
+	oldBase := googleFormsBase
+	googleFormsBase = srv.URL + "/forms"
+	defer func() { googleFormsBase = oldBase }()
+	t.Setenv("DATAFACT_API_KEY", "test-key")
+
+	body := `{"dry_run":true,"saves":{"form_id":"1FAIpQLSdry","fbzx":"tok-9","page_history":"0,1",
+		"entry_ids":[11,12],"entry_mappings":{"Nama":11,"Hobi":12},
+		"questions":[{"id":11,"text":"Nama","kind":"short_answer"},
+			{"id":12,"text":"Hobi","kind":"checkbox","options":["Membaca","Musik"]}]},
+		"answers":[{"Nama":"Budi","Hobi":["Membaca","Musik"]}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/form-injector", bytes.NewReader([]byte(body)))
+	req.Header.Set("Authorization", "Bearer test-key")
+	rec := httptest.NewRecorder()
+	InjectorHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var result InjectResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.DryRun || len(result.Payloads) != 1 || result.Success != 0 {
+		t.Fatalf("result = %+v, want one dry-run payload", result)
+	}
+	p := result.Payloads[0]
+	if p.ResponseURL != googleFormsBase+"/d/e/1FAIpQLSdry/formResponse" || p.Fbzx != "tok-9" || p.PageHistory != "0,1" {
+		t.Errorf("payload = %+v", p)
+	}
+	if !reflect.DeepEqual(p.Entries["entry.12"], []string{"Membaca", "Musik"}) || !reflect.DeepEqual(p.Entries["entry.11"], []string{"Budi"}) {
+		t.Errorf("entries = %v", p.Entries)
+	}
+
+	// Body adalah form yang akan di-POST apa adanya
+	form, err := url.ParseQuery(p.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("fbzx") != "tok-9" || form.Get("pageHistory") != "0,1" || form.Get("partialResponse") != string(p.PartialResponse) {
+		t.Errorf("body = %s", p.Body)
+	}
+	if !bytes.Contains(p.PartialResponse, []byte(`[null,12,["Membaca","Musik"],0]`)) {
+		t.Errorf("partial_response = %s, want checkbox entry", p.PartialResponse)
+	}
+}
+
+func TestBranchAnswer(t *testing.T) {
+	q := QuestionItem{Options: []string{"Ya", "Tidak"}, HasOther: true}
+	cases := []struct {
+		val  interface{}
+		want string
+	}{
+		{"Ya", "Ya"},
+		{"Lainnya", otherOptionValue},
+		{[]interface{}{"Tidak"}, "Tidak"},
+		{[]string{otherOptionValue}, otherOptionValue},
+		{map[string]interface{}{"other": "x"}, otherOptionValue},
+	}
+	for _, tc := range cases {
+		if got, ok := branchAnswer(q, tc.val); !ok || got != tc.want {
+			t.Errorf("branchAnswer(%v) = %q, %v; want %q", tc.val, got, ok, tc.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// --- Models Parser ---
+
+// ParseRequest: input parsing offline. Isi salah satu:
+//   - HTML: HTML viewform lengkap (misal hasil "Save Page As")
+//   - Data: array FB_PUBLIC_LOAD_DATA_ mentah (array JSON atau string JSON)
+//
+// Body juga boleh langsung berupa HTML mentah atau array JSON mentah.
+type ParseRequest struct {
+	HTML string          `json:"html"`
+	Data json.RawMessage `json:"data"`
+}
+
+// --- Logic ---
+
+// parseFormInput menentukan jenis input (HTML / array / wrapper JSON)
+// lalu mem-parsing tanpa akses network.
+func parseFormInput(body []byte) (*ScrapeResponse, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("body kosong")
+	}
+
+	switch trimmed[0] {
+	case '<':
+		return parseFormHTML(string(trimmed))
+	case '[':
+		return parseFormDataJSON(trimmed)
+	}
+
+	var req ParseRequest
+	if err := json.Unmarshal(trimmed, &req); err != nil {
+		return nil, fmt.Errorf("body harus HTML, array JSON, atau object {html|data}: %v", err)
+	}
+	if req.HTML != "" {
+		return parseFormHTML(req.HTML)
+	}
+	if len(req.Data) > 0 && string(req.Data) != "null" {
+		return parseFormDataJSON(req.Data)
+	}
+	return nil, fmt.Errorf("html atau data wajib diisi")
+}
+
+func parseFormDataJSON(raw json.RawMessage) (*ScrapeResponse, error) {
+	var rawData []interface{}
+	// parseFlexibleJSON dari form-injector.go (terima array atau string JSON)
+	if err := parseFlexibleJSON(raw, &rawData); err != nil {
+		return nil, fmt.Errorf("gagal parsing JSON form structure: %v", err)
+	}
+	return parseFormData(rawData, "", 0)
+}
+
+// --- Handler ---
+
+// ParserHandler: versi offline dari ScrapperHandler, mem-parsing HTML /
+// FB_PUBLIC_LOAD_DATA_ yang dikirim caller. Contoh input & output ada di
+// testdata/forms.
+func ParserHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		http.Error(w, "failed to read body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := parseFormInput(body)
+	if err != nil {
+		// Input offline yang tidak bisa diparse adalah kesalahan input caller,
+		// bukan kegagalan server: layout_changed dikirim sebagai 422.
+		se := asScrapeError(err)
+		status := se.Status()
+		if se.Code == ErrCodeLayoutChanged {
+			status = http.StatusUnprocessableEntity
+		}
+		writeScrapeErrorStatus(w, se, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "tulis ulang testdata/forms/*.golden.json dari output parser")
+
+// TestParseFormInputGolden mem-parsing setiap fixture di testdata/forms
+// lalu membandingkannya dengan <nama>.golden.json.
+// Regenerasi: go test ./api/v1 -run Golden -update
+func TestParseFormInputGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "forms", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tested := 0
+	for _, path := range fixtures {
+		if strings.HasSuffix(path, ".golden.json") {
+			continue
+		}
+		name := filepath.Base(path)
+		golden := strings.TrimSuffix(path, filepath.Ext(path)) + ".golden.json"
+		tested++
+
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := parseFormInput(input)
+			if err != nil {
+				t.Fatalf("parseFormInput: %v", err)
+			}
+			got, err := json.MarshalIndent(parsed, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s (run with -update after checking the change):\n%s", golden, firstDiff(string(want), string(got)))
+			}
+		})
+	}
+	if tested == 0 {
+		t.Fatal("no fixtures found in testdata/forms")
+	}
+}
+
+// firstDiff menampilkan baris pertama yang berbeda agar kegagalan mudah dibaca.
+func firstDiff(want, got string) string {
+	wl, gl := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+func TestParseOtherOptionGoTo(t *testing.T) {
+	data := `[null,[null,[
+		[1,"Belanja",null,2,[[11,[["Ya",null,500],["",null,-3,null,1]],1]]],
+		[500,"Toko",null,8],
+		[2,"Nama toko",null,0,[[12,null,1]]]
+	]]]`
+	parsed, err := parseFormInput([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := parsed.Questions[0]
+	want := map[string]int64{"Ya": 500, otherOptionValue: navSubmit}
+	if !q.HasOther || len(q.Options) != 1 || !reflect.DeepEqual(q.GoTo, want) {
+		t.Errorf("question = %+v, want options [Ya], has_other and go_to %v", q, want)
+	}
+}
+
+func TestParsePagesFromPageBreaks(t *testing.T) {
+	// Navigasi page break berlaku untuk akhir halaman sebelumnya
+	data := `[null,[null,[
+		[1,"Nama",null,0,[[11,null,1]]],
+		[500,"Pengalaman","Isi jika pernah belanja",8],
+		[2,"Platform",null,0,[[12,null,0]]],
+		[3,"Alasan",null,1,[[13,null,0]]],
+		[700,"Penutup",null,8,null,-3]
+	],null,null,null,null,null,null,"Survei Belanja"],"Survei Belanja"]`
+	parsed, err := parseFormInput([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FormPage{
+		{Index: 0, Title: "Survei Belanja", EntryIDs: []int64{11}},
+		{Index: 1, ID: 500, Title: "Pengalaman", Description: "Isi jika pernah belanja", EntryIDs: []int64{12, 13}, GoTo: navSubmit},
+		{Index: 2, ID: 700, Title: "Penutup"},
+	}
+	if !reflect.DeepEqual(parsed.Pages, want) {
+		t.Errorf("pages = %+v, want %+v", parsed.Pages, want)
+	}
+	if !reflect.DeepEqual(parsed.Saves.Pages, parsed.Pages) {
+		t.Errorf("saves pages = %+v, want same as pages", parsed.Saves.Pages)
+	}
+	if parsed.Saves.PageHistory != "0,1,2" {
+		t.Errorf("page_history = %q, want 0,1,2", parsed.Saves.PageHistory)
+	}
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ======================================================
+// Form Text Renderer
+// ======================================================
+
+// Template render yang tersedia. "numbered" adalah default: kuesioner
+// bernomor lengkap dengan tipe, opsi, label skala, tanda wajib & section.
+const (
+	TemplateNumbered = "numbered"
+	TemplateCompact  = "compact"
+	TemplateMarkdown = "markdown"
+)
+
+var kindLabels = map[QuestionKind]string{
+	KindShortAnswer:    "Jawaban singkat",
+	KindParagraph:      "Paragraf",
+	KindMultipleChoice: "Pilihan ganda (pilih satu)",
+	KindDropdown:       "Dropdown (pilih satu)",
+	KindCheckbox:       "Kotak centang (boleh lebih dari satu)",
+	KindLinearScale:    "Skala linear (pilih satu angka)",
+	KindGrid:           "Grid pilihan ganda (satu kolom per baris)",
+	KindCheckboxGrid:   "Grid kotak centang (boleh lebih dari satu kolom per baris)",
+	KindDate:           "Tanggal (YYYY-MM-DD)",
+	KindTime:           "Waktu (HH:MM)",
+	KindFileUpload:     "Unggah file (tidak bisa diisi otomatis)",
+	KindUnknown:        "Tidak dikenal",
+}
+
+func kindLabel(q QuestionItem) string {
+	switch {
+	case q.Kind == KindDate && q.Date != nil && q.Date.IncludeTime:
+		return "Tanggal & waktu (YYYY-MM-DD HH:MM)"
+	case q.Kind == KindTime && q.Time != nil && q.Time.Duration:
+		return "Durasi (HH:MM:SS)"
+	}
+	if label, ok := kindLabels[q.Kind]; ok {
+		return label
+	}
+	return string(q.Kind)
+}
+
+// renderBlock: satu item yang dirender di dalam halaman, pertanyaan atau
+// media, diurutkan berdasarkan posisi aslinya di form.
+type renderBlock struct {
+	position int
+	question *QuestionItem
+	media    *MediaItem
+}
+
+type renderPage struct {
+	page   FormPage
+	blocks []renderBlock
+}
+
+// groupForRender menyusun pertanyaan & media per halaman sesuai urutan
+// form. Saves tanpa Pages dianggap satu halaman.
+func groupForRender(saves FormSaveState) []renderPage {
+	pages := saves.Pages
+	if len(pages) == 0 {
+		pages = []FormPage{{Index: 0}}
+		for _, q := range saves.Questions {
+			pages[0].EntryIDs = append(pages[0].EntryIDs, q.ID)
+		}
+	}
+
+	pageOf := make(map[int64]int)
+	for i, p := range pages {
+		for _, id := range p.EntryIDs {
+			pageOf[id] = i
+		}
+	}
+
+	out := make([]renderPage, len(pages))
+	for i, p := range pages {
+		out[i].page = p
+	}
+	for i := range saves.Questions {
+		q := &saves.Questions[i]
+		idx := pageOf[q.ID]
+		out[idx].blocks = append(out[idx].blocks, renderBlock{position: q.Position, question: q})
+	}
+	for i := range saves.Media {
+		m := &saves.Media[i]
+		if m.PageIndex < 0 || m.PageIndex >= len(out) {
+			continue
+		}
+		out[m.PageIndex].blocks = append(out[m.PageIndex].blocks, renderBlock{position: m.Position, media: m})
+	}
+	for i := range out {
+		sort.SliceStable(out[i].blocks, func(a, b int) bool {
+			return out[i].blocks[a].position < out[i].blocks[b].position
+		})
+	}
+	return out
+}
+
+// describeValidation meringkas aturan validasi agar bisa dibaca model.
+func describeValidation(rules []ValidationRule) string {
+	parts := make([]string, 0, len(rules))
+	for _, r := range rules {
+		desc := r.Type + " " + strings.ReplaceAll(r.Operator, "_", " ")
+		if len(r.Args) > 0 {
+			desc += " " + strings.Join(r.Args, " - ")
+		}
+		if r.Message != "" {
+			desc += fmt.Sprintf(" (%q)", r.Message)
+		}
+		parts = append(parts, desc)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func optionList(q QuestionItem) []string {
+	opts := append([]string{}, q.Options...)
+	if q.HasOther {
+		opts = append(opts, "Lainnya: (isian bebas)")
+	}
+	return opts
+}
+
+func scaleLine(q QuestionItem) string {
+	if q.Scale == nil {
+		return ""
+	}
+	line := fmt.Sprintf("Skala %d-%d", q.Scale.Min, q.Scale.Max)
+	var labels []string
+	if q.Scale.MinLabel != "" {
+		labels = append(labels, fmt.Sprintf("%d = %s", q.Scale.Min, q.Scale.MinLabel))
+	}
+	if q.Scale.MaxLabel != "" {
+		labels = append(labels, fmt.Sprintf("%d = %s", q.Scale.Max, q.Scale.MaxLabel))
+	}
+	if len(labels) > 0 {
+		line += " (" + strings.Join(labels, ", ") + ")"
+	}
+	return line
+}
+
+func mediaLine(m MediaItem) string {
+	label := "Gambar"
+	if m.Kind == "video" {
+		label = "Video"
+	}
+	line := "[" + label
+	if m.Title != "" {
+		line += ": " + m.Title
+	}
+	if m.URL != "" {
+		line += " - " + m.URL
+	}
+	return line + "]"
+}
+
+// renderFormText mengubah skema hasil scrape menjadi teks kuesioner
+// bernomor yang siap dipakai sebagai {{ $json.form }} di prompt factory.
+func renderFormText(saves FormSaveState, template string) (string, error) {
+	if len(saves.Questions) == 0 {
+		return "", fmt.Errorf("saves tidak berisi skema pertanyaan (scrape ulang form)")
+	}
+
+	var b strings.Builder
+	pages := groupForRender(saves)
+	multiPage := len(pages) > 1
+	number := 0
+
+	title, desc := pages[0].page.Title, pages[0].page.Description
+	switch template {
+	case "", TemplateNumbered, TemplateCompact:
+		if title != "" {
+			b.WriteString(title + "\n")
+		}
+		if desc != "" {
+			b.WriteString(desc + "\n")
+		}
+		b.WriteString("(* = wajib diisi)\n")
+	case TemplateMarkdown:
+		if title != "" {
+			b.WriteString("# " + title + "\n\n")
+		}
+		if desc != "" {
+			b.WriteString(desc + "\n\n")
+		}
+		b.WriteString("_Pertanyaan bertanda * wajib diisi._\n")
+	default:
+		return "", fmt.Errorf("template %q tidak dikenal (pilih %s, %s, atau %s)", template, TemplateNumbered, TemplateCompact, TemplateMarkdown)
+	}
+
+	for pi, rp := range pages {
+		if multiPage {
+			header := fmt.Sprintf("Bagian %d", pi+1)
+			if rp.page.Title != "" && pi > 0 {
+				header += ": " + rp.page.Title
+			}
+			if template == TemplateMarkdown {
+				b.WriteString("\n## " + header + "\n")
+			} else {
+				b.WriteString("\n=== " + header + " ===\n")
+			}
+			if pi > 0 && rp.page.Description != "" {
+				b.WriteString(rp.page.Description + "\n")
+			}
+		}
+
+		for _, block := range rp.blocks {
+			if block.media != nil {
+				b.WriteString("\n" + mediaLine(*block.media) + "\n")
+				continue
+			}
+			number++
+			q := *block.question
+			switch template {
+			case TemplateCompact:
+				renderCompact(&b, number, q)
+			case TemplateMarkdown:
+				renderMarkdown(&b, number, q)
+			default:
+				renderNumbered(&b, number, q)
+			}
+		}
+	}
+
+	return strings.TrimSpace(b.String()) + "\n", nil
+}
+
+func requiredMark(q QuestionItem) string {
+	if q.Required {
+		return " *"
+	}
+	return ""
+}
+
+func renderNumbered(b *strings.Builder, n int, q QuestionItem) {
+	fmt.Fprintf(b, "\n%d. %s%s\n", n, q.Text, requiredMark(q))
+	if q.HelpText != "" {
+		fmt.Fprintf(b, "   Keterangan: %s\n", q.HelpText)
+	}
+	fmt.Fprintf(b, "   Tipe: %s\n", kindLabel(q))
+	if line := scaleLine(q); line != "" {
+		fmt.Fprintf(b, "   %s\n", line)
+	}
+	if len(q.Rows) > 0 {
+		b.WriteString("   Baris:\n")
+		for _, row := range q.Rows {
+			fmt.Fprintf(b, "   - %s\n", row.Label)
+		}
+		fmt.Fprintf(b, "   Kolom: %s\n", strings.Join(q.Options, " | "))
+	} else if q.Kind != KindLinearScale {
+		if opts := optionList(q); len(opts) > 0 {
+			b.WriteString("   Opsi:\n")
+			for _, o := range opts {
+				fmt.Fprintf(b, "   - %s\n", o)
+			}
+		}
+	}
+	if len(q.Validation) > 0 {
+		fmt.Fprintf(b, "   Validasi: %s\n", describeValidation(q.Validation))
+	}
+}
+
+func renderCompact(b *strings.Builder, n int, q QuestionItem) {
+	fmt.Fprintf(b, "%d. %s%s [%s]", n, q.Text, requiredMark(q), kindLabel(q))
+	if line := scaleLine(q); line != "" {
+		fmt.Fprintf(b, " %s", line)
+	} else if len(q.Rows) > 0 {
+		labels := make([]string, len(q.Rows))
+		for i, row := range q.Rows {
+			labels[i] = row.Label
+		}
+		fmt.Fprintf(b, " baris {%s} kolom {%s}", strings.Join(labels, " | "), strings.Join(q.Options, " | "))
+	} else if opts := optionList(q); len(opts) > 0 {
+		fmt.Fprintf(b, " {%s}", strings.Join(opts, " | "))
+	}
+	if q.HelpText != "" {
+		fmt.Fprintf(b, " -- %s", q.HelpText)
+	}
+	b.WriteString("\n")
+}
+
+func renderMarkdown(b *strings.Builder, n int, q QuestionItem) {
+	fmt.Fprintf(b, "\n**%d. %s**%s  \n", n, q.Text, requiredMark(q))
+	if q.HelpText != "" {
+		fmt.Fprintf(b, "_%s_  \n", q.HelpText)
+	}
+	fmt.Fprintf(b, "Tipe: %s\n", kindLabel(q))
+	if line := scaleLine(q); line != "" {
+		fmt.Fprintf(b, "\n%s\n", line)
+	}
+	if len(q.Rows) > 0 {
+		fmt.Fprintf(b, "\n| Baris | %s |\n|---|%s\n", strings.Join(q.Options, " | "), strings.Repeat("---|", len(q.Options)))
+		for _, row := range q.Rows {
+			fmt.Fprintf(b, "| %s |%s\n", row.Label, strings.Repeat("  |", len(q.Options)))
+		}
+	} else if q.Kind != KindLinearScale {
+		if opts := optionList(q); len(opts) > 0 {
+			b.WriteString("\n")
+			for _, o := range opts {
+				fmt.Fprintf(b, "- %s\n", o)
+			}
+		}
+	}
+	if len(q.Validation) > 0 {
+		fmt.Fprintf(b, "\nValidasi: %s\n", describeValidation(q.Validation))
+	}
+}
+
+// ======================================================
+// Source Resolution (saves / form_url)
+// ======================================================
+
+// loadFormSchema mengambil skema form dari saves (object atau string JSON)
+// atau, jika kosong, dengan scrape form_url (lewat cache skema).
+func loadFormSchema(saves json.RawMessage, formURL string, refresh bool) (FormSaveState, error) {
+	var state FormSaveState
+	if len(saves) > 0 && string(saves) != "null" {
+		// parseFlexibleJSON dari form-injector.go
+		if err := parseFlexibleJSON(saves, &state); err != nil {
+			return state, fmt.Errorf("invalid saves format: %v", err)
+		}
+		return state, nil
+	}
+	if formURL == "" {
+		return state, fmt.Errorf("saves atau form_url wajib diisi")
+	}
+	scraped, err := scrapeGoogleForm(formURL, refresh)
+	if err != nil {
+		return state, err
+	}
+	return scraped.Saves, nil
+}
+
+// ======================================================
+// HTTP Handler
+// ======================================================
+
+type RenderRequest struct {
+	Saves    json.RawMessage `json:"saves"`
+	FormURL  string          `json:"form_url"`
+	Template string          `json:"template"` // numbered (default) | compact | markdown
+	Refresh  bool            `json:"refresh,omitempty"`
+}
+
+func RendererHandler(w http.ResponseWriter, r *http.Request) {
+	if err := mustAuthorize(r); err != nil {
+		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RenderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	schema, err := loadFormSchema(req.Saves, req.FormURL, req.Refresh)
+	if err != nil {
+		writeFormSourceError(w, err)
+		return
+	}
+
+	text, err := renderFormText(schema, req.Template)
+	if err != nil {
+		http.Error(w, "render failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(text))
+}
+
+// writeFormSourceError: error scrape dikirim dengan kode & status typed,
+// error input lainnya sebagai 400.
+func writeFormSourceError(w http.ResponseWriter, err error) {
+	var se *ScrapeError
+	if errors.As(err, &se) {
+		writeScrapeError(w, se)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,703 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ======================================================
+// JSON Schema Answer Document
+// ======================================================
+
+// JSONSchema: subset JSON Schema (draft 2020-12) yang dipakai untuk
+// mendeskripsikan satu dokumen jawaban (object key -> jawaban).
+// Subset yang sama didukung oleh validateSchema di bawah.
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type  interface{}   `json:"type,omitempty"` // string atau []string
+	Enum  []interface{} `json:"enum,omitempty"`
+	AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+	AllOf []*JSONSchema `json:"allOf,omitempty"`
+	Not   *JSONSchema   `json:"not,omitempty"`
+
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+
+	Items       *JSONSchema `json:"items,omitempty"`
+	MinItems    *int        `json:"minItems,omitempty"`
+	MaxItems    *int        `json:"maxItems,omitempty"`
+	UniqueItems bool        `json:"uniqueItems,omitempty"`
+
+	Pattern   string `json:"pattern,omitempty"`
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Format    string `json:"format,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	// Anotasi: entry ID Google Form untuk properti ini
+	EntryID int64 `json:"x-entry-id,omitempty"`
+}
+
+const (
+	SchemaKeyByText    = "text"     // key = teks pertanyaan (default)
+	SchemaKeyByEntryID = "entry_id" // key = entry ID sebagai string
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	numberPattern   = `^-?\d+(\.\d+)?$`
+)
+
+func intPtr(n int) *int           { return &n }
+func floatPtr(f float64) *float64 { return &f }
+func boolPtr(b bool) *bool        { return &b }
+func stringEnum(opts []string) []interface{} {
+	out := make([]interface{}, len(opts))
+	for i, o := range opts {
+		out[i] = o
+	}
+	return out
+}
+
+// buildAnswerSchema menurunkan JSON Schema dokumen jawaban dari skema
+// form: enum untuk pertanyaan pilihan, array untuk checkbox, object per
+// baris untuk grid, pattern/format untuk teks bervalidasi. File upload
+// tidak masuk karena tidak bisa diisi lewat injector.
+func buildAnswerSchema(saves FormSaveState, keyBy string) (*JSONSchema, error) {
+	if keyBy == "" {
+		keyBy = SchemaKeyByText
+	}
+	if keyBy != SchemaKeyByText && keyBy != SchemaKeyByEntryID {
+		return nil, fmt.Errorf("key_by %q tidak dikenal (pilih %s atau %s)", keyBy, SchemaKeyByText, SchemaKeyByEntryID)
+	}
+	if len(saves.Questions) == 0 {
+		return nil, fmt.Errorf("saves tidak berisi skema pertanyaan (scrape ulang form)")
+	}
+
+	root := &JSONSchema{
+		Schema:               jsonSchemaDraft,
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: boolPtr(false),
+	}
+	if len(saves.Pages) > 0 {
+		root.Title = saves.Pages[0].Title
+		root.Description = saves.Pages[0].Description
+	}
+
+	// Form bercabang: hanya halaman pertama yang pasti dilalui, jadi hanya
+	// pertanyaan di sana yang masuk "required" level dokumen.
+	alwaysReached := map[int64]bool{}
+	branching := false
+	for _, p := range saves.Pages {
+		if p.GoTo != 0 {
+			branching = true
+		}
+	}
+	for _, q := range saves.Questions {
+		if len(q.GoTo) > 0 {
+			branching = true
+		}
+	}
+	for i, p := range saves.Pages {
+		if i == 0 || !branching {
+			for _, id := range p.EntryIDs {
+				alwaysReached[id] = true
+			}
+		}
+	}
+	if len(saves.Pages) == 0 {
+		for _, q := range saves.Questions {
+			alwaysReached[q.ID] = true
+		}
+	}
+
+	if saves.Settings != nil && saves.Settings.EmailCollection != EmailCollectionNone {
+		root.Properties["email"] = &JSONSchema{Type: "string", Format: "email", Description: "Alamat email responden"}
+		if saves.Settings.EmailCollection == EmailCollectionInput {
+			root.Required = append(root.Required, "email")
+		}
+	}
+
+	for _, q := range saves.Questions {
+		if q.Kind == KindFileUpload {
+			continue
+		}
+		key := q.Text
+		if keyBy == SchemaKeyByEntryID || key == "" {
+			key = strconv.FormatInt(q.ID, 10)
+		}
+		if _, dup := root.Properties[key]; dup {
+			// Teks pertanyaan kembar: pakai entry ID agar tidak tertimpa
+			key = strconv.FormatInt(q.ID, 10)
+		}
+
+		prop := questionSchema(q, keyBy)
+		prop.EntryID = q.ID
+		root.Properties[key] = prop
+		if q.Required {
+			if alwaysReached[q.ID] {
+				root.Required = append(root.Required, key)
+			} else {
+				prop.Description = strings.TrimSpace(prop.Description + " Wajib diisi jika section ini dilalui.")
+			}
+		}
+	}
+	return root, nil
+}
+
+// questionSchema: schema untuk satu pertanyaan.
+func questionSchema(q QuestionItem, keyBy string) *JSONSchema {
+	s := &JSONSchema{Title: q.Text, Description: q.HelpText}
+
+	switch q.Kind {
+	case KindMultipleChoice, KindDropdown:
+		applyChoice(s, q)
+	case KindCheckbox:
+		s.Type = "array"
+		s.Items = &JSONSchema{}
+		applyChoice(s.Items, q)
+		s.UniqueItems = true
+		if q.Required {
+			s.MinItems = intPtr(1)
+		}
+	case KindLinearScale:
+		if q.Scale != nil {
+			s.AnyOf = []*JSONSchema{
+				{Type: "integer", Minimum: floatPtr(float64(q.Scale.Min)), Maximum: floatPtr(float64(q.Scale.Max))},
+				{Type: "string", Enum: stringEnum(q.Options)},
+			}
+		} else {
+			s.Type = "string"
+			s.Enum = stringEnum(q.Options)
+		}
+	case KindGrid, KindCheckboxGrid:
+		s.Type = "object"
+		s.Properties = make(map[string]*JSONSchema, len(q.Rows))
+		s.AdditionalProperties = boolPtr(false)
+		for _, row := range q.Rows {
+			key := row.Label
+			if keyBy == SchemaKeyByEntryID {
+				key = strconv.FormatInt(row.ID, 10)
+			}
+			cell := &JSONSchema{Type: "string", Enum: stringEnum(q.Options)}
+			if q.Kind == KindCheckboxGrid {
+				cell = &JSONSchema{Type: "array", Items: cell, UniqueItems: true}
+				if q.Required {
+					cell.MinItems = intPtr(1)
+				}
+			}
+			cell.EntryID = row.ID
+			s.Properties[key] = cell
+			if q.Required {
+				s.Required = append(s.Required, key)
+			}
+		}
+	case KindDate:
+		s.Type = "string"
+		s.Pattern = `^\d{4}-\d{2}-\d{2}$`
+		s.Format = "date"
+		if q.Date != nil && q.Date.IncludeTime {
+			s.Pattern = `^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2})?$`
+			s.Format = ""
+		}
+	case KindTime:
+		s.Type = "string"
+		s.Pattern = `^\d{1,2}:\d{2}(:\d{2})?$`
+		if q.Time != nil && q.Time.Duration {
+			s.Pattern = `^\d+:\d{2}:\d{2}$`
+		}
+	default:
+		s.Type = "string"
+	}
+
+	if q.Required && q.Kind.IsFreeText() {
+		s.MinLength = intPtr(1)
+	}
+	for _, rule := range q.Validation {
+		applyValidationRule(s, rule)
+	}
+	return s
+}
+
+// applyChoice: enum opsi; jika ada "Other", teks bebas / {"other": ...}
+// juga diterima (sama seperti extractOtherAnswers di injector).
+func applyChoice(s *JSONSchema, q QuestionItem) {
+	if !q.HasOther {
+		s.Type = "string"
+		s.Enum = stringEnum(q.Options)
+		return
+	}
+	s.AnyOf = []*JSONSchema{
+		{Type: "string", Enum: stringEnum(q.Options)},
+		{Type: "string", Description: "Jawaban Other (isian bebas)"},
+		{
+			Type:                 "object",
+			Properties:           map[string]*JSONSchema{"other": {Type: "string"}},
+			Required:             []string{"other"},
+			AdditionalProperties: boolPtr(false),
+		},
+	}
+}
+
+// applyValidationRule menerjemahkan ValidationRule ke keyword JSON Schema.
+// Aturan angka: jawaban boleh number atau string angka; batas nilai
+// berlaku untuk keduanya (validateSchema mengecek batas pada string angka).
+func applyValidationRule(s *JSONSchema, rule ValidationRule) {
+	a, hasA := ruleArgFloat(rule, 0)
+	b, _ := ruleArgFloat(rule, 1)
+	arg := ""
+	if len(rule.Args) > 0 {
+		arg = rule.Args[0]
+	}
+	sub := &JSONSchema{}
+
+	switch rule.Type {
+	case "checkbox":
+		if !hasA {
+			return
+		}
+		switch rule.Operator {
+		case "at_least":
+			s.MinItems = intPtr(int(a))
+		case "at_most":
+			s.MaxItems = intPtr(int(a))
+		case "exactly":
+			s.MinItems, s.MaxItems = intPtr(int(a)), intPtr(int(a))
+		}
+		return
+	case "number":
+		s.Type = []string{"number", "string"}
+		sub.Pattern = numberPattern
+		switch rule.Operator {
+		case "gt":
+			sub.ExclusiveMinimum = floatPtr(a)
+		case "gte":
+			sub.Minimum = floatPtr(a)
+		case "lt":
+			sub.ExclusiveMaximum = floatPtr(a)
+		case "lte":
+			sub.Maximum = floatPtr(a)
+		case "eq":
+			sub.Minimum, sub.Maximum = floatPtr(a), floatPtr(a)
+		case "ne":
+			sub.Not = numberRange(a, a)
+		case "between":
+			sub.Minimum, sub.Maximum = floatPtr(a), floatPtr(b)
+		case "not_between":
+			sub.Not = numberRange(a, b)
+		case "whole_number":
+			sub.Pattern = `^-?\d+$`
+			sub.MultipleOf = floatPtr(1)
+		}
+	case "text":
+		switch rule.Operator {
+		case "contains":
+			sub.Pattern = regexp.QuoteMeta(arg)
+		case "not_contains":
+			sub.Not = &JSONSchema{Type: "string", Pattern: regexp.QuoteMeta(arg)}
+		case "email":
+			sub.Format = "email"
+		case "url":
+			sub.Format = "uri"
+		}
+	case "length":
+		if !hasA {
+			return
+		}
+		if rule.Operator == "max_length" {
+			sub.MaxLength = intPtr(int(a))
+		} else {
+			sub.MinLength = intPtr(int(a))
+		}
+	case "regex":
+		if arg == "" {
+			return
+		}
+		switch rule.Operator {
+		case "contains":
+			sub.Pattern = arg
+		case "matches":
+			sub.Pattern = "^(?:" + arg + ")$"
+		case "not_contains":
+			sub.Not = &JSONSchema{Type: "string", Pattern: arg}
+		case "not_matches":
+			sub.Not = &JSONSchema{Type: "string", Pattern: "^(?:" + arg + ")$"}
+		}
+	default:
+		return
+	}
+	sub.Description = rule.Message
+	s.AllOf = append(s.AllOf, sub)
+}
+
+// numberRange: number atau string angka dalam [min, max], dipakai untuk
+// "not" pada operator ne / not_between.
+func numberRange(min, max float64) *JSONSchema {
+	return &JSONSchema{Type: []string{"number", "string"}, Pattern: numberPattern, Minimum: floatPtr(min), Maximum: floatPtr(max)}
+}
+
+// ======================================================
+// Schema Validation
+// ======================================================
+
+// validateSchema memvalidasi dokumen (hasil json.Unmarshal ke
+// interface{}) terhadap schema. Hasil berupa Violation dengan Key =
+// JSON pointer lokasi pelanggaran dan Code = keyword yang gagal.
+func validateSchema(s *JSONSchema, doc interface{}) []Violation {
+	return validateNode(s, doc, "", 0)
+}
+
+func validateNode(s *JSONSchema, v interface{}, path string, entryID int64) []Violation {
+	if s == nil {
+		return nil
+	}
+	if s.EntryID != 0 {
+		entryID = s.EntryID
+	}
+	fail := func(code, format string, args ...interface{}) Violation {
+		ptr := path
+		if ptr == "" {
+			ptr = "/"
+		}
+		return Violation{
+			EntryID: entryID,
+			Key:     ptr,
+			Level:   violationError,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+
+	if s.Type != nil && !matchesType(s.Type, v) {
+		return []Violation{fail("type", "harus bertipe %v, diterima %s", typeNames(s.Type), jsonTypeOf(v))}
+	}
+
+	var vs []Violation
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		vs = append(vs, fail("enum", "%s bukan salah satu nilai yang diizinkan", compactJSON(v)))
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, alt := range s.AnyOf {
+			if len(validateNode(alt, v, path, entryID)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			vs = append(vs, fail("anyOf", "%s tidak cocok dengan bentuk jawaban mana pun", compactJSON(v)))
+		}
+	}
+	for _, sub := range s.AllOf {
+		for _, subV := range validateNode(sub, v, path, entryID) {
+			if sub.Description != "" {
+				subV.Message = sub.Description + " (" + subV.Message + ")"
+			}
+			vs = append(vs, subV)
+		}
+	}
+	if s.Not != nil && len(validateNode(s.Not, v, path, entryID)) == 0 {
+		vs = append(vs, fail("not", "%s cocok dengan pola yang dilarang", compactJSON(v)))
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, key := range s.Required {
+			if _, ok := val[key]; !ok {
+				vs = append(vs, fail("required", "key %q wajib diisi", key))
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "/" + jsonPointerEscape(k)
+			if prop, ok := s.Properties[k]; ok {
+				vs = append(vs, validateNode(prop, val[k], child, entryID)...)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				vs = append(vs, Violation{Key: child, Level: violationError, Code: "additionalProperties", Message: fmt.Sprintf("key %q tidak dikenal", k)})
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			vs = append(vs, fail("minItems", "minimal %d item, diterima %d", *s.MinItems, len(val)))
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			vs = append(vs, fail("maxItems", "maksimal %d item, diterima %d", *s.MaxItems, len(val)))
+		}
+		if s.UniqueItems {
+			seen := map[string]bool{}
+			for _, item := range val {
+				k := compactJSON(item)
+				if seen[k] {
+					vs = append(vs, fail("uniqueItems", "item %s muncul lebih dari sekali", k))
+				}
+				seen[k] = true
+			}
+		}
+		for i, item := range val {
+			vs = append(vs, validateNode(s.Items, item, path+"/"+strconv.Itoa(i), entryID)...)
+		}
+	case string:
+		length := utf8.RuneCountInString(val)
+		if s.MinLength != nil && length < *s.MinLength {
+			vs = append(vs, fail("minLength", "minimal %d karakter, diterima %d", *s.MinLength, length))
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			vs = append(vs, fail("maxLength", "maksimal %d karakter, diterima %d", *s.MaxLength, length))
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(val) {
+				vs = append(vs, fail("pattern", "%q tidak cocok dengan pola %q", val, s.Pattern))
+			}
+		}
+		if msg := checkFormat(s.Format, val); msg != "" {
+			vs = append(vs, fail("format", "%s", msg))
+		}
+		// String angka (aturan number menerima "17") ikut dicek batasnya,
+		// sama seperti checkTextRule di validator injector.
+		if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+			vs = append(vs, checkNumberBounds(s, f, fail)...)
+		}
+	case float64:
+		vs = append(vs, checkNumberBounds(s, val, fail)...)
+	}
+	return vs
+}
+
+// checkNumberBounds: minimum/maximum/exclusive*/multipleOf untuk satu nilai angka.
+func checkNumberBounds(s *JSONSchema, val float64, fail func(code, format string, args ...interface{}) Violation) []Violation {
+	var vs []Violation
+	if s.Minimum != nil && val < *s.Minimum {
+		vs = append(vs, fail("minimum", "%v kurang dari %v", val, *s.Minimum))
+	}
+	if s.Maximum != nil && val > *s.Maximum {
+		vs = append(vs, fail("maximum", "%v lebih dari %v", val, *s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && val <= *s.ExclusiveMinimum {
+		vs = append(vs, fail("exclusiveMinimum", "%v harus lebih dari %v", val, *s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && val >= *s.ExclusiveMaximum {
+		vs = append(vs, fail("exclusiveMaximum", "%v harus kurang dari %v", val, *s.ExclusiveMaximum))
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if q := val / *s.MultipleOf; q != math.Trunc(q) {
+			vs = append(vs, fail("multipleOf", "%v bukan kelipatan %v", val, *s.MultipleOf))
+		}
+	}
+	return vs
+}
+
+func typeNames(t interface{}) string {
+	if list, ok := t.([]string); ok {
+		return strings.Join(list, " atau ")
+	}
+	return fmt.Sprint(t)
+}
+
+func matchesType(t interface{}, v interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		actual := jsonTypeOf(v)
+		return actual == tt || (tt == "number" && actual == "integer")
+	case []string:
+		for _, one := range tt {
+			if matchesType(one, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	want := compactJSON(v)
+	for _, e := range enum {
+		if compactJSON(e) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func compactJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func checkFormat(format, s string) string {
+	switch format {
+	case "email":
+		if _, err := mail.ParseAddress(s); err != nil {
+			return fmt.Sprintf("%q bukan email valid", s)
+		}
+	case "uri":
+		if u, err := url.ParseRequestURI(s); err != nil || u.Host == "" {
+			return fmt.Sprintf("%q bukan URL valid", s)
+		}
+	case "date":
+		if _, err := parseDateAnswer(s, nil); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+func jsonPointerEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// decodeAnswerDocs membaca satu dokumen jawaban (object) atau array
+// dokumen. Fence markdown ```json dari output LLM dibuang dulu.
+func decodeAnswerDocs(raw []byte) ([]interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if bytes.HasPrefix(raw, []byte("```")) {
+		raw = bytes.TrimPrefix(raw, []byte("```json"))
+		raw = bytes.TrimPrefix(raw, []byte("```"))
+		raw = bytes.TrimSuffix(bytes.TrimSpace(raw), []byte("```"))
+	}
+
+	var doc interface{}
+	// parseFlexibleJSON dari form-injector.go (terima JSON atau string JSON)
+	if err := parseFlexibleJSON(raw, &doc); err != nil {
+		return nil, fmt.Errorf("jawaban bukan JSON valid: %v", err)
+	}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		return []interface{}{v}, nil
+	case []interface{}:
+		return v, nil
+	}
+	return nil, fmt.Errorf("jawaban harus object atau array object")
+}
+
+// validateAnswerDocs memvalidasi setiap dokumen, hanya baris yang punya
+// pelanggaran yang dikembalikan.
+func validateAnswerDocs(s *JSONSchema, docs []interface{}) []RowViolations {
+	var rows []RowViolations
+	for i, doc := range docs {
+		if vs := validateSchema(s, doc); len(vs) > 0 {
+			rows = append(rows, RowViolations{Row: i, Violations: vs})
+		}
+	}
+	return rows
+}
+
+// ======================================================
+// HTTP Handler
+// ======================================================
+
+// SchemaRequest: tanpa answers -> kirim JSON Schema; dengan answers ->
+// validasi answers terhadap schema tersebut.
+type SchemaRequest struct {
+	Saves   json.RawMessage `json:"saves"`
+	FormURL string          `json:"form_url"`
+	KeyBy   string          `json:"key_by"` // text (default) | entry_id
+	Refresh bool            `json:"refresh,omitempty"`
+	Answers json.RawMessage `json:"answers,omitempty"`
+}
+
+type SchemaValidationResponse struct {
+	Valid   bool            `json:"valid"`
+	Total   int             `json:"total"`
+	Invalid int             `json:"invalid"`
+	Rows    []RowViolations `json:"rows"`
+}
+
+func SchemaHandler(w http.ResponseWriter, r *http.Request) {
+	if err := mustAuthorize(r); err != nil {
+		http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// loadFormSchema dari form-renderer.go
+	saves, err := loadFormSchema(req.Saves, req.FormURL, req.Refresh)
+	if err != nil {
+		writeFormSourceError(w, err)
+		return
+	}
+	schema, err := buildAnswerSchema(saves, req.KeyBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Answers) == 0 || string(req.Answers) == "null" {
+		w.Header().Set("Content-Type", "application/schema+json")
+		json.NewEncoder(w).Encode(schema)
+		return
+	}
+
+	docs, err := decodeAnswerDocs(req.Answers)
+	if err != nil {
+		http.Error(w, "invalid answers: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	rows := validateAnswerDocs(schema, docs)
+	resp := SchemaValidationResponse{
+		Valid:   len(rows) == 0,
+		Total:   len(docs),
+		Invalid: len(rows),
+		Rows:    rows,
+	}
+	if resp.Rows == nil {
+		resp.Rows = []RowViolations{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
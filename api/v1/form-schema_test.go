@@ -0,0 +1,47 @@
+package handler
+
+import "testing"
+
+func TestSchemaAndValidatorAgree(t *testing.T) {
+	age := QuestionItem{ID: 1, Text: "Umur", Kind: KindShortAnswer, Required: true,
+		Validation: []ValidationRule{{Type: "number", Operator: "between", Args: []string{"17", "60"}}}}
+	notFive := QuestionItem{ID: 2, Text: "Angka", Kind: KindShortAnswer,
+		Validation: []ValidationRule{{Type: "number", Operator: "ne", Args: []string{"5"}}}}
+	upload := QuestionItem{ID: 3, Text: "CV", Kind: KindFileUpload, Required: true}
+	saves := FormSaveState{Questions: []QuestionItem{age, notFive, upload}}
+
+	schema, err := buildAnswerSchema(saves, SchemaKeyByText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		doc    map[string]interface{}
+		answer map[int64]interface{}
+		valid  bool
+	}{
+		{"number in range", map[string]interface{}{"Umur": 20.0}, map[int64]interface{}{1: 20.0}, true},
+		{"numeric string in range", map[string]interface{}{"Umur": "20"}, map[int64]interface{}{1: "20"}, true},
+		{"number out of range", map[string]interface{}{"Umur": 150.0}, map[int64]interface{}{1: 150.0}, false},
+		{"numeric string out of range", map[string]interface{}{"Umur": "150"}, map[int64]interface{}{1: "150"}, false},
+		{"not a number", map[string]interface{}{"Umur": "dua puluh"}, map[int64]interface{}{1: "dua puluh"}, false},
+		{"ne as number", map[string]interface{}{"Umur": 20.0, "Angka": 5.0}, map[int64]interface{}{1: 20.0, 2: 5.0}, false},
+		{"ne as numeric string", map[string]interface{}{"Umur": 20.0, "Angka": "5"}, map[int64]interface{}{1: 20.0, 2: "5"}, false},
+		{"ne other value", map[string]interface{}{"Umur": 20.0, "Angka": "6"}, map[int64]interface{}{1: 20.0, 2: "6"}, true},
+		// File upload wajib tidak ada di schema dan tidak memblokir baris
+		{"required file upload unanswered", map[string]interface{}{"Umur": "30"}, map[int64]interface{}{1: "30"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaVs := validateSchema(schema, tc.doc)
+			if got := !hasBlockingViolation(schemaVs); got != tc.valid {
+				t.Errorf("validateSchema valid = %v, want %v (%+v)", got, tc.valid, schemaVs)
+			}
+			answerVs := validateAnswers(tc.answer, saves.Questions, nil, nil)
+			if got := !hasBlockingViolation(answerVs); got != tc.valid {
+				t.Errorf("validateAnswers valid = %v, want %v (%+v)", got, tc.valid, answerVs)
+			}
+		})
+	}
+}
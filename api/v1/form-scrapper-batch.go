@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// --- Models Batch Scrapper ---
+
+// BatchScrapeRequest: daftar form yang di-scrape sekaligus. Concurrency
+// opsional (default defaultBatchConcurrency, maksimal maxBatchConcurrency).
+type BatchScrapeRequest struct {
+	FormURLs    []string `json:"form_urls"`
+	Concurrency int      `json:"concurrency"`
+	Refresh     bool     `json:"refresh,omitempty"` // abaikan cache skema
+}
+
+// BatchScrapeItem: hasil satu URL. Tepat satu dari Data / Error terisi;
+// Status adalah HTTP status yang akan didapat jika URL ini di-scrape
+// sendiri lewat /api/v1/form-scrapper.
+type BatchScrapeItem struct {
+	Index   int             `json:"index"`
+	FormURL string          `json:"form_url"`
+	Status  int             `json:"status"`
+	Data    *ScrapeResponse `json:"data,omitempty"`
+	Error   *ScrapeError    `json:"error,omitempty"`
+}
+
+type BatchScrapeResponse struct {
+	Total   int               `json:"total"`
+	Success int               `json:"success"`
+	Failed  int               `json:"failed"`
+	Results []BatchScrapeItem `json:"results"`
+}
+
+const (
+	defaultBatchConcurrency = 4
+	maxBatchConcurrency     = 10
+	maxBatchURLs            = 50
+)
+
+// --- Logic ---
+
+// scrapeBatch men-scrape semua URL dengan concurrency terbatas. Urutan
+// hasil sama dengan urutan input; error satu form tidak menggagalkan
+// form lain.
+func scrapeBatch(urls []string, concurrency int, refresh bool) BatchScrapeResponse {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	if concurrency > maxBatchConcurrency {
+		concurrency = maxBatchConcurrency
+	}
+
+	results := make([]BatchScrapeItem, len(urls))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, u := range urls {
+		wg.Add(1)
+		go func(idx int, formURL string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			item := BatchScrapeItem{Index: idx, FormURL: formURL}
+			if strings.TrimSpace(formURL) == "" {
+				item.Error = newScrapeError(ErrCodeInvalidURL, "form_url is required", nil)
+			} else if data, err := scrapeGoogleForm(formURL, refresh); err != nil {
+				item.Error = asScrapeError(err)
+			} else {
+				item.Data = data
+				item.Status = http.StatusOK
+			}
+			if item.Error != nil {
+				item.Status = item.Error.Status()
+				body := scrapeErrorBody(item.Error)
+				item.Error = &body
+			}
+			results[idx] = item
+		}(i, u)
+	}
+	wg.Wait()
+
+	resp := BatchScrapeResponse{Total: len(urls), Results: results}
+	for _, item := range results {
+		if item.Error != nil {
+			resp.Failed++
+		} else {
+			resp.Success++
+		}
+	}
+	return resp
+}
+
+// --- Handler ---
+
+// BatchScrapperHandler: versi batch dari ScrapperHandler. Selalu 200 selama
+// request valid; status per form ada di results[i].status.
+func BatchScrapperHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BatchScrapeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+	if len(req.FormURLs) == 0 {
+		http.Error(w, "form_urls is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.FormURLs) > maxBatchURLs {
+		http.Error(w, fmt.Sprintf("too many form_urls (max %d)", maxBatchURLs), http.StatusBadRequest)
+		return
+	}
+
+	resp := scrapeBatch(req.FormURLs, req.Concurrency, req.Refresh)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
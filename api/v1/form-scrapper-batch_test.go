@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScrapeBatch(t *testing.T) {
+	const loadData = `[null,[null,[[1,"Kota",null,0,[[11,null,0]]]]],null,null,null,null,null,null,"Form"]`
+	var inFlight, maxInFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		if strings.Contains(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<html><script>var FB_PUBLIC_LOAD_DATA_ = %s;</script></html>", loadData)
+	}))
+	defer srv.Close()
+	prevBase, prevCache := googleFormsBase, formSchemaCache
+	googleFormsBase = srv.URL + "/forms"
+	formSchemaCache = newSchemaCache(time.Hour, 32)
+	defer func() { googleFormsBase, formSchemaCache = prevBase, prevCache }()
+
+	var urls []string
+	for i := 0; i < 8; i++ {
+		urls = append(urls, fmt.Sprintf("%s/forms/d/e/form%d/viewform", srv.URL, i))
+	}
+	urls = append(urls, srv.URL+"/forms/d/e/missing/viewform", " ")
+
+	resp := scrapeBatch(urls, 3, false)
+	if resp.Total != 10 || resp.Success != 8 || resp.Failed != 2 {
+		t.Errorf("total/success/failed = %d/%d/%d, want 10/8/2", resp.Total, resp.Success, resp.Failed)
+	}
+	for i, item := range resp.Results {
+		if item.Index != i || item.FormURL != urls[i] {
+			t.Errorf("results[%d] = index %d url %s, want input order", i, item.Index, item.FormURL)
+		}
+	}
+	if item := resp.Results[8]; item.Status != http.StatusNotFound || item.Error == nil || item.Error.Code != ErrCodeFormNotFound {
+		t.Errorf("missing form = %+v", item)
+	}
+	if item := resp.Results[9]; item.Status != http.StatusBadRequest || item.Error == nil || item.Error.Code != ErrCodeInvalidURL {
+		t.Errorf("blank url = %+v", item)
+	}
+	if item := resp.Results[0]; item.Status != http.StatusOK || item.Data == nil || len(item.Data.Questions) != 1 {
+		t.Errorf("ok form = %+v", item)
+	}
+	if maxInFlight > 3 {
+		t.Errorf("max concurrent fetches = %d, want <= 3", maxInFlight)
+	}
+}
+
+func TestBatchScrapperHandlerLimits(t *testing.T) {
+	tooMany := make([]string, maxBatchURLs+1)
+	for i := range tooMany {
+		tooMany[i] = "https://forms.gle/x"
+	}
+	body, _ := json.Marshal(BatchScrapeRequest{FormURLs: tooMany})
+	cases := map[string]string{
+		"empty":    `{"form_urls": []}`,
+		"too many": string(body),
+		"bad json": `{"form_urls": "x"}`,
+	}
+	for name, body := range cases {
+		rec := httptest.NewRecorder()
+		BatchScrapperHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/form-scrapper/batch", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
@@ -2,48 +2,692 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 )
 
 // --- Models Scrapper ---
 type ScrapeRequest struct {
 	FormURL string `json:"form_url"`
+	// Refresh: abaikan cache skema dan scrape ulang (form-cache.go)
+	Refresh bool `json:"refresh,omitempty"`
+}
+
+// QuestionKind adalah tipe pertanyaan hasil mapping dari kode tipe item
+// Google Form (qArray[3]). Dipakai downstream untuk tahu apakah jawaban
+// berupa satu nilai, banyak nilai, teks bebas, atau tanggal/waktu.
+type QuestionKind string
+
+const (
+	KindShortAnswer    QuestionKind = "short_answer"
+	KindParagraph      QuestionKind = "paragraph"
+	KindMultipleChoice QuestionKind = "multiple_choice"
+	KindDropdown       QuestionKind = "dropdown"
+	KindCheckbox       QuestionKind = "checkbox"
+	KindLinearScale    QuestionKind = "linear_scale"
+	KindGrid           QuestionKind = "grid"
+	KindCheckboxGrid   QuestionKind = "checkbox_grid"
+	KindDate           QuestionKind = "date"
+	KindTime           QuestionKind = "time"
+	KindFileUpload     QuestionKind = "file_upload"
+	KindUnknown        QuestionKind = "unknown"
+)
+
+// Kode tipe item di FB_PUBLIC_LOAD_DATA_
+const (
+	itemTypeShortAnswer    = 0
+	itemTypeParagraph      = 1
+	itemTypeMultipleChoice = 2
+	itemTypeDropdown       = 3
+	itemTypeCheckbox       = 4
+	itemTypeLinearScale    = 5
+	itemTypeTitle          = 6
+	itemTypeGrid           = 7
+	itemTypePageBreak      = 8
+	itemTypeDate           = 9
+	itemTypeTime           = 10
+	itemTypeImage          = 11
+	itemTypeVideo          = 12
+	itemTypeFileUpload     = 13
+)
+
+func questionKindFromType(itemType int) QuestionKind {
+	switch itemType {
+	case itemTypeShortAnswer:
+		return KindShortAnswer
+	case itemTypeParagraph:
+		return KindParagraph
+	case itemTypeMultipleChoice:
+		return KindMultipleChoice
+	case itemTypeDropdown:
+		return KindDropdown
+	case itemTypeCheckbox:
+		return KindCheckbox
+	case itemTypeLinearScale:
+		return KindLinearScale
+	case itemTypeGrid:
+		return KindGrid
+	case itemTypeDate:
+		return KindDate
+	case itemTypeTime:
+		return KindTime
+	case itemTypeFileUpload:
+		return KindFileUpload
+	default:
+		return KindUnknown
+	}
+}
+
+// IsMultiValue: jawaban boleh lebih dari satu nilai (checkbox).
+func (k QuestionKind) IsMultiValue() bool {
+	return k == KindCheckbox || k == KindCheckboxGrid
+}
+
+// IsChoice: jawaban harus salah satu dari Options.
+func (k QuestionKind) IsChoice() bool {
+	switch k {
+	case KindMultipleChoice, KindDropdown, KindCheckbox, KindLinearScale, KindGrid, KindCheckboxGrid:
+		return true
+	}
+	return false
+}
+
+// IsFreeText: jawaban berupa teks bebas.
+func (k QuestionKind) IsFreeText() bool {
+	return k == KindShortAnswer || k == KindParagraph
+}
+
+// Target navigasi "go to section" pada opsi / page break. Nilai positif
+// adalah item ID page break tujuan; navSubmit berarti langsung submit,
+// nilai lain dianggap lanjut ke halaman berikutnya.
+const navSubmit int64 = -3
+
+// ScaleMeta: metadata linear scale (batas nilai + label ujung).
+type ScaleMeta struct {
+	Min      int64  `json:"min"`
+	Max      int64  `json:"max"`
+	MinLabel string `json:"min_label,omitempty"`
+	MaxLabel string `json:"max_label,omitempty"`
+}
+
+// DateMeta: metadata pertanyaan tanggal.
+type DateMeta struct {
+	IncludeYear bool `json:"include_year"`
+	IncludeTime bool `json:"include_time"`
+}
+
+// TimeMeta: metadata pertanyaan waktu. Duration = true berarti
+// format "durasi" (jam/menit/detik), bukan jam dalam sehari.
+type TimeMeta struct {
+	Duration bool `json:"duration"`
+}
+
+// ValidationRule: aturan "response validation" pada pertanyaan.
+// Type: number | text | length | regex | checkbox.
+type ValidationRule struct {
+	Type     string   `json:"type"`
+	Operator string   `json:"operator"`
+	Args     []string `json:"args,omitempty"`
+	Message  string   `json:"message,omitempty"` // pesan error custom dari pemilik form
+}
+
+// validationOperators: kode subtype validasi (detailInner[4][i][1]) ->
+// {tipe, operator}. Kode tipe di index 0 tidak konsisten antar versi form,
+// jadi mapping memakai subtype saja.
+var validationOperators = map[int64][2]string{
+	1: {"number", "gt"}, 2: {"number", "gte"}, 3: {"number", "lt"}, 4: {"number", "lte"},
+	5: {"number", "eq"}, 6: {"number", "ne"}, 7: {"number", "between"}, 8: {"number", "not_between"},
+	9: {"number", "is_number"}, 10: {"number", "whole_number"},
+	100: {"text", "contains"}, 101: {"text", "not_contains"}, 102: {"text", "email"}, 103: {"text", "url"},
+	200: {"checkbox", "at_least"}, 201: {"checkbox", "at_most"}, 204: {"checkbox", "exactly"},
+	202: {"length", "max_length"}, 203: {"length", "min_length"},
+	299: {"regex", "contains"}, 300: {"regex", "not_contains"}, 301: {"regex", "matches"}, 302: {"regex", "not_matches"},
+}
+
+func parseValidationRules(raw []interface{}) []ValidationRule {
+	var rules []ValidationRule
+	for _, r := range raw {
+		ruleArr, _ := r.([]interface{})
+		subtype, ok := rawInt(ruleArr, 1)
+		if !ok {
+			continue
+		}
+		op, known := validationOperators[subtype]
+		if !known {
+			continue
+		}
+		rule := ValidationRule{Type: op[0], Operator: op[1], Message: rawString(ruleArr, 3)}
+		for _, a := range rawList(ruleArr, 2) {
+			if a != nil {
+				rule.Args = append(rule.Args, fmt.Sprint(a))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// GridRow: satu baris pada grid. Setiap baris punya entry ID sendiri,
+// kolomnya sama untuk semua baris (QuestionItem.Options).
+type GridRow struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// MediaItem: item gambar/video di form. Position = urutan item di form
+// (sama dengan QuestionItem.Position) agar bisa disisipkan di antara
+// pertanyaan saat dirender.
+type MediaItem struct {
+	ItemID    int64  `json:"item_id"`
+	Kind      string `json:"kind"` // image | video
+	Title     string `json:"title,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Position  int    `json:"position"`
+	PageIndex int    `json:"page_index"`
+}
+
+// parseMediaItem membaca item gambar (tipe 11) / video (tipe 12).
+// qArray[6][0] = [url atau ID video, lebar, tinggi, align].
+func parseMediaItem(qArray []interface{}, itemType int) MediaItem {
+	itemID, _ := rawInt(qArray, 0)
+	m := MediaItem{
+		ItemID: itemID,
+		Kind:   "image",
+		Title:  rawString(qArray, 1),
+		URL:    rawString(rawList(rawList(qArray, 6), 0), 0),
+	}
+	if itemType == itemTypeVideo {
+		m.Kind = "video"
+		// Video YouTube kadang hanya berupa ID
+		if m.URL != "" && !strings.Contains(m.URL, "://") {
+			m.URL = "https://www.youtube.com/watch?v=" + m.URL
+		}
+	}
+	return m
 }
 
 type QuestionItem struct {
-	ID      int64    `json:"id"`
-	Text    string   `json:"text"`
-	Options []string `json:"options,omitempty"`
+	ID       int64        `json:"id"`
+	Text     string       `json:"text"`
+	HelpText string       `json:"help_text,omitempty"` // deskripsi pertanyaan (qArray[2])
+	Position int          `json:"position"`            // urutan item di form
+	Kind     QuestionKind `json:"kind"`
+	Options  []string     `json:"options,omitempty"`
+
+	// HasOther: ada opsi "Other:" dengan isian bebas (tidak masuk Options)
+	HasOther bool `json:"has_other,omitempty"`
+
+	Required   bool             `json:"required"`
+	Validation []ValidationRule `json:"validation,omitempty"`
+
+	// Metadata spesifik per tipe (hanya salah satu yang terisi)
+	Scale *ScaleMeta `json:"scale,omitempty"`
+	Date  *DateMeta  `json:"date,omitempty"`
+	Time  *TimeMeta  `json:"time,omitempty"`
+	Rows  []GridRow  `json:"rows,omitempty"`
+
+	// Branching: opsi -> target navigasi (item ID halaman, atau -3 = submit).
+	// Hanya terisi jika "go to section based on answer" aktif.
+	GoTo map[string]int64 `json:"go_to,omitempty"`
+}
+
+// RowByLabel mencari baris grid berdasarkan label (case-insensitive)
+// atau entry ID dalam bentuk string.
+func (q QuestionItem) RowByLabel(key string) (GridRow, bool) {
+	key = strings.TrimSpace(key)
+	for _, row := range q.Rows {
+		if strings.EqualFold(row.Label, key) || strconv.FormatInt(row.ID, 10) == key {
+			return row, true
+		}
+	}
+	return GridRow{}, false
+}
+
+// FormPage: satu halaman/section form sesuai urutan yang dilihat responden.
+// Halaman pertama (Index 0) memakai judul & deskripsi form, halaman
+// berikutnya dimulai dari item page break (tipe 8).
+type FormPage struct {
+	Index       int     `json:"index"`
+	ID          int64   `json:"id,omitempty"` // item ID page break (0 untuk halaman pertama)
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	EntryIDs    []int64 `json:"entry_ids"`
+	// Navigasi setelah halaman selesai ("after section ... go to").
+	// 0 = lanjut ke halaman berikutnya.
+	GoTo int64 `json:"go_to,omitempty"`
+}
+
+// FormSettings: pengaturan level form. Email collection: "none",
+// "verified" (email akun Google, wajib login) atau "responder_input".
+type FormSettings struct {
+	EmailCollection     string `json:"email_collection"`
+	RequiresSignIn      bool   `json:"requires_sign_in"`
+	LimitOneResponse    bool   `json:"limit_one_response"`
+	AcceptingResponses  bool   `json:"accepting_responses"`
+	IsQuiz              bool   `json:"is_quiz"`
+	ShuffleQuestions    bool   `json:"shuffle_questions"`
+	ConfirmationMessage string `json:"confirmation_message,omitempty"`
+}
+
+const (
+	EmailCollectionNone     = "none"
+	EmailCollectionVerified = "verified"
+	EmailCollectionInput    = "responder_input"
+)
+
+// Posisi pengaturan form di lvl1 (rawData[1]), hasil observasi
+// FB_PUBLIC_LOAD_DATA_:
+//
+//	lvl1[2]  = [pesan konfirmasi, link kirim lagi, ...]
+//	lvl1[10] = [?, limit 1 respon, acak urutan, ?, ?, ?, mode email]
+//	lvl1[16] = [mode kuis, ...] (null jika bukan kuis)
+const (
+	rawConfirmationIdx  = 2
+	rawSettingsIdx      = 10
+	rawQuizIdx          = 16
+	settingLimitOneIdx  = 1
+	settingShuffleIdx   = 2
+	settingEmailModeIdx = 6
+)
+
+// parseFormSettings membaca pengaturan form. cookieEmail (heuristik HTML)
+// dipakai sebagai sinyal tambahan untuk email & sign-in.
+func parseFormSettings(lvl1 []interface{}, cookieEmail int) FormSettings {
+	settings := rawList(lvl1, rawSettingsIdx)
+	fs := FormSettings{
+		EmailCollection:     EmailCollectionNone,
+		LimitOneResponse:    rawFlag(settings, settingLimitOneIdx),
+		AcceptingResponses:  true,
+		IsQuiz:              rawFlag(rawList(lvl1, rawQuizIdx), 0),
+		ShuffleQuestions:    rawFlag(settings, settingShuffleIdx),
+		ConfirmationMessage: rawString(rawList(lvl1, rawConfirmationIdx), 0),
+	}
+
+	emailMode, _ := rawInt(settings, settingEmailModeIdx)
+	switch {
+	case emailMode == 2 || cookieEmail == 2:
+		fs.EmailCollection = EmailCollectionVerified
+	case emailMode == 3 || cookieEmail == 1:
+		fs.EmailCollection = EmailCollectionInput
+	}
+
+	fs.RequiresSignIn = fs.LimitOneResponse || fs.EmailCollection == EmailCollectionVerified
+	return fs
 }
 
 type ScrapeResponse struct {
 	Description string        `json:"description"`
 	Questions   []QuestionItem `json:"questions"`
+	Pages       []FormPage     `json:"pages"`
+	Media       []MediaItem    `json:"media,omitempty"`
+	Form        *FormRef       `json:"form,omitempty"` // ID kanonik + URL viewform/formResponse
+	Settings    FormSettings   `json:"settings"`
 	CookieEmail int           `json:"cookie_email"` // 0, 1, atau 2 sesuai logika HTML
 	Saves       FormSaveState `json:"saves"`
+	Cache       *CacheStatus  `json:"cache,omitempty"` // status cache skema (hanya hasil scrape live)
+}
+
+// --- Errors Scrapper ---
+
+// ScrapeErrorCode: kode error yang bisa dibaca mesin, dikirim di body
+// response agar pipeline bisa membedakan "form ditutup" dari "parser rusak".
+type ScrapeErrorCode string
+
+const (
+	ErrCodeInvalidURL     ScrapeErrorCode = "invalid_url"
+	ErrCodeFormNotFound   ScrapeErrorCode = "form_not_found"
+	ErrCodeFormClosed     ScrapeErrorCode = "form_closed"
+	ErrCodeSignInRequired ScrapeErrorCode = "sign_in_required"
+	ErrCodeRateLimited    ScrapeErrorCode = "rate_limited"
+	ErrCodeUpstream       ScrapeErrorCode = "upstream_error"
+	ErrCodeLayoutChanged  ScrapeErrorCode = "layout_changed"
+)
+
+// scrapeErrorStatus: mapping kode error -> HTTP status response kita.
+var scrapeErrorStatus = map[ScrapeErrorCode]int{
+	ErrCodeInvalidURL:     http.StatusBadRequest,
+	ErrCodeFormNotFound:   http.StatusNotFound,
+	ErrCodeFormClosed:     http.StatusGone,
+	ErrCodeSignInRequired: http.StatusForbidden,
+	ErrCodeRateLimited:    http.StatusTooManyRequests,
+	ErrCodeUpstream:       http.StatusBadGateway,
+	ErrCodeLayoutChanged:  http.StatusInternalServerError,
+}
+
+type ScrapeError struct {
+	Code           ScrapeErrorCode `json:"error"`
+	Message        string          `json:"message"`
+	UpstreamStatus int             `json:"upstream_status,omitempty"`
+	Err            error           `json:"-"`
+}
+
+func (e *ScrapeError) Error() string {
+	if e.Err != nil {
+		return string(e.Code) + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return string(e.Code) + ": " + e.Message
+}
+
+func (e *ScrapeError) Unwrap() error { return e.Err }
+
+// Status: HTTP status yang dipakai saat error ini dikirim ke caller.
+func (e *ScrapeError) Status() int {
+	if s, ok := scrapeErrorStatus[e.Code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+func newScrapeError(code ScrapeErrorCode, msg string, err error) *ScrapeError {
+	return &ScrapeError{Code: code, Message: msg, Err: err}
+}
+
+// asScrapeError membungkus error biasa sebagai layout_changed agar setiap
+// error scrapper punya kode.
+func asScrapeError(err error) *ScrapeError {
+	var se *ScrapeError
+	if errors.As(err, &se) {
+		return se
+	}
+	return newScrapeError(ErrCodeLayoutChanged, "unexpected scraper error", err)
+}
+
+func writeScrapeError(w http.ResponseWriter, se *ScrapeError) {
+	writeScrapeErrorStatus(w, se, se.Status())
+}
+
+// scrapeErrorBody: salinan error untuk dikirim ke caller, dengan pesan
+// error internal digabung ke Message (Err tidak ikut di-encode).
+func scrapeErrorBody(se *ScrapeError) ScrapeError {
+	body := *se
+	if se.Err != nil {
+		body.Message = se.Message + ": " + se.Err.Error()
+	}
+	return body
+}
+
+func writeScrapeErrorStatus(w http.ResponseWriter, se *ScrapeError, status int) {
+	body := scrapeErrorBody(se)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// --- Raw JSON Helpers ---
+// FB_PUBLIC_LOAD_DATA_ berupa nested array tanpa nama field,
+// helper ini mengakses index dengan aman (nil / zero value jika tidak ada).
+
+func rawAt(arr []interface{}, i int) interface{} {
+	if i < 0 || i >= len(arr) {
+		return nil
+	}
+	return arr[i]
+}
+
+func rawList(arr []interface{}, i int) []interface{} {
+	l, _ := rawAt(arr, i).([]interface{})
+	return l
+}
+
+func rawString(arr []interface{}, i int) string {
+	s, _ := rawAt(arr, i).(string)
+	return s
+}
+
+func rawInt(arr []interface{}, i int) (int64, bool) {
+	f, ok := rawAt(arr, i).(float64)
+	return int64(f), ok
+}
+
+func rawFlag(arr []interface{}, i int) bool {
+	n, ok := rawInt(arr, i)
+	return ok && n == 1
 }
 
 // --- Logic ---
 
-func scrapeGoogleForm(formURL string) (*ScrapeResponse, error) {
-	req, _ := http.NewRequest("GET", formURL, nil)
+// parseQuestionItem membaca satu item pertanyaan (qArray) beserta detail
+// inputnya. Untuk grid, ID = entry ID baris pertama dan semua baris ada
+// di Rows. ok = false jika item tidak punya entry ID.
+func parseQuestionItem(qArray []interface{}, itemType int) (QuestionItem, bool) {
+	detailInner := rawList(rawList(qArray, 4), 0)
+	entryID, ok := rawInt(detailInner, 0)
+	if !ok {
+		return QuestionItem{}, false
+	}
+
+	q := QuestionItem{
+		ID:       entryID,
+		Text:     rawString(qArray, 1),
+		HelpText: rawString(qArray, 2),
+		Kind:     questionKindFromType(itemType),
+		// detailInner[2] = required flag, detailInner[4] = aturan validasi
+		Required:   rawFlag(detailInner, 2),
+		Validation: parseValidationRules(rawList(detailInner, 4)),
+	}
+
+	for _, o := range rawList(detailInner, 1) {
+		if optArr, ok := o.([]interface{}); ok && len(optArr) > 0 {
+			// optArr[4] = 1 menandai opsi "Other:" (label kosong); target
+			// navigasinya disimpan dengan key otherOptionValue
+			var optStr string
+			if rawFlag(optArr, 4) {
+				q.HasOther = true
+				optStr = otherOptionValue
+			} else if label, ok := optArr[0].(string); ok {
+				optStr = label
+				q.Options = append(q.Options, optStr)
+			} else {
+				continue
+			}
+			// optArr[2] = target navigasi jika branching aktif
+			if target, ok := rawInt(optArr, 2); ok && target != 0 {
+				if q.GoTo == nil {
+					q.GoTo = make(map[string]int64)
+				}
+				q.GoTo[optStr] = target
+			}
+		}
+	}
+
+	switch q.Kind {
+	case KindLinearScale:
+		// Options berisi "1".."5", label ujung ada di detailInner[3]
+		scale := &ScaleMeta{}
+		for i, opt := range q.Options {
+			n, err := strconv.ParseInt(opt, 10, 64)
+			if err != nil {
+				continue
+			}
+			if i == 0 || n < scale.Min {
+				scale.Min = n
+			}
+			if i == 0 || n > scale.Max {
+				scale.Max = n
+			}
+		}
+		labels := rawList(detailInner, 3)
+		scale.MinLabel = rawString(labels, 0)
+		scale.MaxLabel = rawString(labels, 1)
+		q.Scale = scale
+	case KindGrid:
+		// Checkbox grid ditandai flag di detailInner[11]
+		if rawFlag(rawList(detailInner, 11), 0) {
+			q.Kind = KindCheckboxGrid
+		}
+		// Setiap baris grid = satu elemen qArray[4]: [rowID, kolom, required, [label]]
+		for _, d := range rawList(qArray, 4) {
+			rowArr, _ := d.([]interface{})
+			rowID, ok := rawInt(rowArr, 0)
+			if !ok {
+				continue
+			}
+			q.Rows = append(q.Rows, GridRow{
+				ID:    rowID,
+				Label: rawString(rawList(rowArr, 3), 0),
+			})
+		}
+	case KindDate:
+		// detailInner[7] = [include_time, include_year]
+		flags := rawList(detailInner, 7)
+		q.Date = &DateMeta{
+			IncludeTime: rawFlag(flags, 0),
+			IncludeYear: rawFlag(flags, 1),
+		}
+	case KindTime:
+		// detailInner[6] = [is_duration]
+		q.Time = &TimeMeta{Duration: rawFlag(rawList(detailInner, 6), 0)}
+	}
+
+	return q, true
+}
+
+// scrapeGoogleForm mengambil skema form lewat cache (form-cache.go).
+// refresh = true memaksa scrape penuh tanpa cache maupun conditional GET.
+func scrapeGoogleForm(formURL string, refresh bool) (*ScrapeResponse, error) {
+	return fetchFormSchema(formURL, refresh, false)
+}
+
+// fetchFormSchema: revalidate = true selalu bertanya ke upstream walau
+// entry cache masih fresh (validator entry dipakai untuk conditional GET,
+// 304 = data cache). Dipakai saat kesegaran skema harus dipastikan.
+func fetchFormSchema(formURL string, refresh, revalidate bool) (*ScrapeResponse, error) {
+	// resolveFormURL dari form-url.go (viewform/edit/prefilled/forms.gle)
+	ref, err := resolveFormURL(formURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, fresh, ok := formSchemaCache.get(ref.ID)
+	if ok && fresh && !refresh && !revalidate {
+		return cached.response(CacheStatusHit, formSchemaCache.now()), nil
+	}
+
+	var cond formValidators
+	if ok && !refresh {
+		cond = cached.validators
+	}
+	content, validators, err := fetchFormHTML(ref.ViewformURL, cond)
+	if errors.Is(err, errNotModified) {
+		if entry, ok := formSchemaCache.touch(ref.ID); ok {
+			return entry.response(CacheStatusRevalidated, formSchemaCache.now()), nil
+		}
+		// Entry terhapus (LRU) di tengah request: scrape penuh
+		content, validators, err = fetchFormHTML(ref.ViewformURL, formValidators{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := parseFormHTML(content)
+	if err != nil {
+		return nil, err
+	}
+	data.Form = &ref
+	data.Saves.FormID = ref.ID
+
+	status := CacheStatusMiss
+	if refresh {
+		status = CacheStatusRefreshed
+	}
+	entry := formSchemaCache.put(ref.ID, data, validators)
+	return entry.response(status, entry.fetchedAt), nil
+}
+
+// formValidators: header ETag / Last-Modified dari response viewform,
+// dipakai untuk conditional GET saat entry cache lewat TTL.
+type formValidators struct {
+	ETag         string
+	LastModified string
+}
+
+var errNotModified = errors.New("form page not modified")
+
+// fetchFormHTML mengunduh halaman viewform via fastClient dan
+// mengklasifikasikan respon non-200, redirect login, dan form tertutup.
+// Jika cond terisi dan upstream menjawab 304, error = errNotModified.
+func fetchFormHTML(formURL string, cond formValidators) (string, formValidators, error) {
+	var validators formValidators
+	u, err := url.Parse(formURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", validators, newScrapeError(ErrCodeInvalidURL, "form_url must be an absolute http(s) URL", err)
+	}
+
+	req, err := http.NewRequest("GET", formURL, nil)
+	if err != nil {
+		return "", validators, newScrapeError(ErrCodeInvalidURL, "cannot build request", err)
+	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	if cond.ETag != "" {
+		req.Header.Set("If-None-Match", cond.ETag)
+	}
+	if cond.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cond.LastModified)
+	}
 
 	resp, err := fastClient.Do(req)
 	if err != nil {
-		return nil, err
+		return "", validators, newScrapeError(ErrCodeUpstream, "cannot reach form host", err)
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	content := string(bodyBytes)
+	if resp.StatusCode == http.StatusNotModified {
+		return "", cond, errNotModified
+	}
+	validators = formValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+
+	if resp.StatusCode != http.StatusOK {
+		se := newScrapeError(ErrCodeUpstream, "unexpected upstream status", nil)
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			se = newScrapeError(ErrCodeFormNotFound, "form does not exist or was deleted", nil)
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			se = newScrapeError(ErrCodeSignInRequired, "form is restricted to signed-in users", nil)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			se = newScrapeError(ErrCodeRateLimited, "form host is rate limiting requests", nil)
+		}
+		se.UpstreamStatus = resp.StatusCode
+		return "", validators, se
+	}
+
+	// Redirect ke halaman login Google = form wajib sign-in
+	if final := resp.Request.URL; final != nil {
+		if final.Host == "accounts.google.com" {
+			return "", validators, newScrapeError(ErrCodeSignInRequired, "form redirects to Google sign-in", nil)
+		}
+		if strings.HasSuffix(final.Path, "/closedform") {
+			return "", validators, newScrapeError(ErrCodeFormClosed, "form is no longer accepting responses", nil)
+		}
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", validators, newScrapeError(ErrCodeUpstream, "failed to read form page", err)
+	}
+	return string(bodyBytes), validators, nil
+}
+
+var (
+	reLoadData         = regexp.MustCompile(`var\s+FB_PUBLIC_LOAD_DATA_\s*=\s*([\s\S]*?);\s*</script>`)
+	reLoadDataFallback = regexp.MustCompile(`var\s+FB_PUBLIC_LOAD_DATA_\s*=\s*(\[[\s\S]*\]);`)
+	reFbzx             = regexp.MustCompile(`name=["']fbzx["']\s+value=["'](.*?)["']`)
+)
+
+// looksClosed: penanda halaman "form tidak lagi menerima respon".
+func looksClosed(content string) bool {
+	return strings.Contains(content, "/closedform") || strings.Contains(content, "no longer accepting responses")
+}
 
+// parseFormHTML mem-parsing HTML viewform (hasil fetch atau export
+// tersimpan) tanpa akses network. Saves.FormID diambil dari action form
+// jika ada.
+func parseFormHTML(content string) (*ScrapeResponse, error) {
 	// --- LOGIC BARU: Cek Raw HTML String ---
 	// Kita lakukan pengecekan string mentah sebelum parsing JSON yang berat.
 	// Prioritas: Cek login (2) dulu, baru cek autocomplete (1).
@@ -60,31 +704,57 @@ func scrapeGoogleForm(formURL string) (*ScrapeResponse, error) {
 	// Jika tidak keduanya, tetap 0
 
 	// 1. Ekstrak FB_PUBLIC_LOAD_DATA_
-	re := regexp.MustCompile(`var\s+FB_PUBLIC_LOAD_DATA_\s*=\s*([\s\S]*?);\s*</script>`)
-	match := re.FindStringSubmatch(content)
+	match := reLoadData.FindStringSubmatch(content)
 
 	if len(match) < 2 {
-		reFallback := regexp.MustCompile(`var\s+FB_PUBLIC_LOAD_DATA_\s*=\s*(\[[\s\S]*\]);`)
-		match = reFallback.FindStringSubmatch(content)
+		match = reLoadDataFallback.FindStringSubmatch(content)
 	}
 
 	if len(match) < 2 {
-		return nil, fmt.Errorf("gagal menemukan data form (FB_PUBLIC_LOAD_DATA_)")
+		// Halaman tanpa data form: cek dulu apakah ini halaman tutup / login
+		if looksClosed(content) {
+			return nil, newScrapeError(ErrCodeFormClosed, "form is no longer accepting responses", nil)
+		}
+		if strings.Contains(content, "accounts.google.com/ServiceLogin") || strings.Contains(content, "accounts.google.com/v3/signin") {
+			return nil, newScrapeError(ErrCodeSignInRequired, "form page requires Google sign-in", nil)
+		}
+		return nil, newScrapeError(ErrCodeLayoutChanged, "gagal menemukan data form (FB_PUBLIC_LOAD_DATA_)", nil)
 	}
 
 	jsonStr := match[1]
 	var rawData []interface{}
 	if err := json.Unmarshal([]byte(jsonStr), &rawData); err != nil {
-		return nil, fmt.Errorf("gagal parsing JSON form structure: %v", err)
+		return nil, newScrapeError(ErrCodeLayoutChanged, "gagal parsing JSON form structure", err)
 	}
 
-	// 2. Cari Token FBZX
+	// 2. Cari Token FBZX (fallback ke rawData[14] di parseFormData)
 	var fbzx string
-	reFbzx := regexp.MustCompile(`name=["']fbzx["']\s+value=["'](.*?)["']`)
-	fbzxMatch := reFbzx.FindStringSubmatch(content)
-	if len(fbzxMatch) > 1 {
+	if fbzxMatch := reFbzx.FindStringSubmatch(content); len(fbzxMatch) > 1 {
 		fbzx = fbzxMatch[1]
-	} else {
+	}
+
+	data, err := parseFormData(rawData, fbzx, cookieEmail)
+	if err != nil {
+		return nil, err
+	}
+	// Halaman form yang masih memuat data tapi sudah ditutup pemiliknya
+	if looksClosed(content) {
+		data.Settings.AcceptingResponses = false
+		data.Saves.Settings.AcceptingResponses = false
+	}
+	if id := formIDFromHTML(content); id != "" {
+		ref := newFormRef(id)
+		data.Form = &ref
+		data.Saves.FormID = id
+	}
+	return data, nil
+}
+
+// parseFormData mem-parsing array FB_PUBLIC_LOAD_DATA_ mentah menjadi
+// ScrapeResponse. fbzx dari HTML diutamakan, jika kosong diambil dari
+// rawData[14].
+func parseFormData(rawData []interface{}, fbzx string, cookieEmail int) (*ScrapeResponse, error) {
+	if fbzx == "" {
 		if len(rawData) > 14 {
 			if val, ok := rawData[14].(string); ok {
 				fbzx = val
@@ -96,26 +766,32 @@ func scrapeGoogleForm(formURL string) (*ScrapeResponse, error) {
 
 	// 3. Parsing Pertanyaan & Page History
 	if len(rawData) < 2 {
-		return nil, fmt.Errorf("struktur JSON invalid")
+		return nil, newScrapeError(ErrCodeLayoutChanged, "struktur JSON invalid", nil)
 	}
 
 	lvl1, ok := rawData[1].([]interface{})
 	if !ok || len(lvl1) < 2 {
-		return nil, fmt.Errorf("gagal akses level 1")
+		return nil, newScrapeError(ErrCodeLayoutChanged, "gagal akses level 1", nil)
 	}
 
 	rawQuestions, ok := lvl1[1].([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("gagal akses list pertanyaan")
+		return nil, newScrapeError(ErrCodeLayoutChanged, "gagal akses list pertanyaan", nil)
 	}
 
 	var questions []QuestionItem
 	var entryIDs []int64
 	entryMappings := make(map[string]int64)
 
-	pageCount := 0
+	desc, _ := lvl1[0].(string)
+	formTitle := rawString(lvl1, 8)
+	if formTitle == "" {
+		formTitle = rawString(rawData, 3)
+	}
+	pages := []FormPage{{Index: 0, Title: formTitle, Description: desc}}
+	var media []MediaItem
 	
-	for _, item := range rawQuestions {
+	for position, item := range rawQuestions {
 		qArray, ok := item.([]interface{})
 		if !ok || len(qArray) < 4 {
 			continue
@@ -127,51 +803,40 @@ func scrapeGoogleForm(formURL string) (*ScrapeResponse, error) {
 			itemType = int(tFloat)
 		}
 
-		if itemType == 8 {
-			pageCount++
-			continue
-		}
-
-		if len(qArray) < 5 {
-			continue
-		}
-		
-		inputDetails, ok := qArray[4].([]interface{})
-		if !ok || len(inputDetails) == 0 {
+		if itemType == itemTypePageBreak {
+			pageID, _ := rawInt(qArray, 0)
+			// Page break menyimpan navigasi untuk akhir halaman SEBELUMNYA
+			if target, ok := rawInt(qArray, 5); ok && target != 0 {
+				pages[len(pages)-1].GoTo = target
+			}
+			pages = append(pages, FormPage{
+				Index:       len(pages),
+				ID:          pageID,
+				Title:       rawString(qArray, 1),
+				Description: rawString(qArray, 2),
+			})
 			continue
 		}
 
-		detailInner, ok := inputDetails[0].([]interface{})
-		if !ok || len(detailInner) == 0 {
+		if itemType == itemTypeImage || itemType == itemTypeVideo {
+			m := parseMediaItem(qArray, itemType)
+			m.Position = position
+			m.PageIndex = len(pages) - 1
+			media = append(media, m)
 			continue
 		}
 
-		idFloat, ok := detailInner[0].(float64)
+		q, ok := parseQuestionItem(qArray, itemType)
 		if !ok {
 			continue
 		}
-		entryID := int64(idFloat)
-		qText, _ := qArray[1].(string)
-
-		var options []string
-		if len(detailInner) > 1 {
-			if optsRaw, ok := detailInner[1].([]interface{}); ok {
-				for _, o := range optsRaw {
-					if optArr, ok := o.([]interface{}); ok && len(optArr) > 0 {
-						if optStr, ok := optArr[0].(string); ok {
-							options = append(options, optStr)
-						}
-					}
-				}
-			}
-		}
+		q.Position = position
+		entryID, qText := q.ID, q.Text
 
-		questions = append(questions, QuestionItem{
-			ID:      entryID,
-			Text:    qText,
-			Options: options,
-		})
+		questions = append(questions, q)
 		entryIDs = append(entryIDs, entryID)
+		current := &pages[len(pages)-1]
+		current.EntryIDs = append(current.EntryIDs, entryID)
 
 		if qText != "" {
 			entryMappings[qText] = entryID
@@ -179,23 +844,30 @@ func scrapeGoogleForm(formURL string) (*ScrapeResponse, error) {
 	}
 
 	var pageHistoryParts []string
-	for i := 0; i <= pageCount; i++ {
+	for i := range pages {
 		pageHistoryParts = append(pageHistoryParts, strconv.Itoa(i))
 	}
 	finalPageHistory := strings.Join(pageHistoryParts, ",")
 
-	desc, _ := lvl1[0].(string)
+	settings := parseFormSettings(lvl1, cookieEmail)
 
 	return &ScrapeResponse{
 		Description: desc,
 		Questions:   questions,
+		Pages:       pages,
+		Media:       media,
 		CookieEmail: cookieEmail, // Menggunakan hasil cek HTML di atas
+		Settings:    settings,
 		Saves: FormSaveState{
-			FormID:        "scraped_" + strconv.FormatInt(time.Now().Unix(), 10),
 			Fbzx:          fbzx,
 			PageHistory:   finalPageHistory,
 			EntryIDs:      entryIDs,
 			EntryMappings: entryMappings,
+			Questions:     questions,
+			Pages:         pages,
+			Media:         media,
+			SchemaHash:    computeSchemaHash(questions, pages),
+			Settings:      &settings,
 		},
 	}, nil
 }
@@ -216,13 +888,13 @@ func ScrapperHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.FormURL == "" {
-		http.Error(w, "form_url is required", http.StatusBadRequest)
+		writeScrapeError(w, newScrapeError(ErrCodeInvalidURL, "form_url is required", nil))
 		return
 	}
 
-	data, err := scrapeGoogleForm(req.FormURL)
+	data, err := scrapeGoogleForm(req.FormURL, req.Refresh)
 	if err != nil {
-		http.Error(w, "scraping failed: "+err.Error(), http.StatusInternalServerError)
+		writeScrapeError(w, asScrapeError(err))
 		return
 	}
 
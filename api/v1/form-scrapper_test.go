@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrapperHandlerErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/forms/d/e/missing/viewform":
+			http.NotFound(w, r)
+		case "/forms/d/e/private/viewform":
+			w.WriteHeader(http.StatusForbidden)
+		case "/forms/d/e/busy/viewform":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/forms/d/e/down/viewform":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/forms/d/e/closed/viewform":
+			http.Redirect(w, r, "/forms/d/e/closed/closedform", http.StatusFound)
+		case "/forms/d/e/closed/closedform":
+			w.Write([]byte("<html>This form is no longer accepting responses</html>"))
+		default:
+			w.Write([]byte("<html>halaman tanpa data form</html>"))
+		}
+	}))
+	defer srv.Close()
+	prevBase, prevCache := googleFormsBase, formSchemaCache
+	googleFormsBase = srv.URL + "/forms"
+	formSchemaCache = newSchemaCache(time.Hour, 16)
+	defer func() { googleFormsBase, formSchemaCache = prevBase, prevCache }()
+
+	cases := []struct {
+		id       string
+		status   int
+		code     ScrapeErrorCode
+		upstream int
+	}{
+		{"missing", http.StatusNotFound, ErrCodeFormNotFound, http.StatusNotFound},
+		{"private", http.StatusForbidden, ErrCodeSignInRequired, http.StatusForbidden},
+		{"busy", http.StatusTooManyRequests, ErrCodeRateLimited, http.StatusTooManyRequests},
+		{"down", http.StatusBadGateway, ErrCodeUpstream, http.StatusServiceUnavailable},
+		{"closed", http.StatusGone, ErrCodeFormClosed, 0},
+		{"garbage", http.StatusInternalServerError, ErrCodeLayoutChanged, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.id, func(t *testing.T) {
+			body := `{"form_url":"` + srv.URL + `/forms/d/e/` + c.id + `/viewform"}`
+			rec := httptest.NewRecorder()
+			ScrapperHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/form-scrapper", strings.NewReader(body)))
+			if rec.Code != c.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, c.status, rec.Body)
+			}
+			var se ScrapeError
+			if err := json.NewDecoder(rec.Body).Decode(&se); err != nil {
+				t.Fatal(err)
+			}
+			if se.Code != c.code || se.UpstreamStatus != c.upstream {
+				t.Errorf("body = %+v, want code %s upstream %d", se, c.code, c.upstream)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	ScrapperHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/form-scrapper", strings.NewReader(`{"form_url":"bukan link"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid url: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAsScrapeErrorWrapsPlainErrors(t *testing.T) {
+	se := asScrapeError(errNotModified)
+	if se.Code != ErrCodeLayoutChanged || se.Status() != http.StatusInternalServerError {
+		t.Errorf("plain error = %+v (status %d), want layout_changed 500", se, se.Status())
+	}
+	typed := newScrapeError(ErrCodeFormClosed, "tutup", nil)
+	if got := asScrapeError(typed); got != typed {
+		t.Errorf("typed error was re-wrapped: %+v", got)
+	}
+}
+
+func TestParseFormSettings(t *testing.T) {
+	lvl1 := func(settings []interface{}, quiz []interface{}) []interface{} {
+		l := make([]interface{}, 17)
+		l[2] = []interface{}{"Terima kasih!"}
+		l[10] = settings
+		l[16] = quiz
+		return l
+	}
+	cases := []struct {
+		name        string
+		lvl1        []interface{}
+		cookieEmail int
+		want        FormSettings
+	}{
+		{"defaults", lvl1(nil, nil), 0, FormSettings{
+			EmailCollection: EmailCollectionNone, AcceptingResponses: true, ConfirmationMessage: "Terima kasih!"}},
+		{"limit one response + shuffle + quiz", lvl1([]interface{}{nil, 1.0, 1.0}, []interface{}{1.0}), 0, FormSettings{
+			EmailCollection: EmailCollectionNone, RequiresSignIn: true, LimitOneResponse: true, AcceptingResponses: true,
+			IsQuiz: true, ShuffleQuestions: true, ConfirmationMessage: "Terima kasih!"}},
+		{"verified email", lvl1([]interface{}{nil, nil, nil, nil, nil, nil, 2.0}, nil), 0, FormSettings{
+			EmailCollection: EmailCollectionVerified, RequiresSignIn: true, AcceptingResponses: true, ConfirmationMessage: "Terima kasih!"}},
+		{"responder input from html", lvl1(nil, nil), 1, FormSettings{
+			EmailCollection: EmailCollectionInput, AcceptingResponses: true, ConfirmationMessage: "Terima kasih!"}},
+	}
+	for _, c := range cases {
+		if got := parseFormSettings(c.lvl1, c.cookieEmail); got != c.want {
+			t.Errorf("%s: settings = %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// =====================
+// Form URL Normalization
+// =====================
+
+// FormRef: identitas kanonik sebuah Google Form. ID adalah public form ID
+// (segmen setelah /forms/d/e/), sama untuk link viewform, prefilled,
+// formResponse maupun forms.gle.
+type FormRef struct {
+	ID          string `json:"form_id"`
+	ViewformURL string `json:"viewform_url"`
+	ResponseURL string `json:"response_url"`
+}
+
+// googleFormsBase: prefix URL semua link form kanonik. Bisa diarahkan ke
+// server pengganti (misal package fakeform) lewat DATAFACT_FORMS_BASE_URL,
+// contoh "http://localhost:9090/forms", agar scrape & inject bisa diuji
+// tanpa menyentuh Google.
+var googleFormsBase = strings.TrimRight(getenv("DATAFACT_FORMS_BASE_URL", "https://docs.google.com/forms"), "/")
+
+// formsOrigin: scheme + host dari googleFormsBase, untuk header Origin.
+func formsOrigin() string {
+	u, err := url.Parse(googleFormsBase)
+	if err != nil || u.Host == "" {
+		return "https://docs.google.com"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// formsHost: host dari googleFormsBase (lowercase).
+func formsHost() string {
+	return strings.ToLower(strings.SplitN(formsOrigin(), "://", 2)[1])
+}
+
+var (
+	// /forms/d/e/<publicID>/... (opsional /u/<n>/ untuk multi-akun)
+	rePublicFormPath = regexp.MustCompile(`/forms/(?:u/\d+/)?d/e/([A-Za-z0-9_-]+)`)
+	// /forms/d/<editID>/... (link editor, perlu redirect ke public ID)
+	reEditFormPath = regexp.MustCompile(`/forms/(?:u/\d+/)?d/([A-Za-z0-9_-]+)`)
+	// action="<...>/forms/d/e/<publicID>/formResponse" di HTML viewform
+	reFormAction = regexp.MustCompile(`/forms/(?:u/\d+/)?d/e/([A-Za-z0-9_-]+)/formResponse`)
+	// Public ID polos tanpa URL (selalu diawali 1FAIpQL)
+	rePlainFormID = regexp.MustCompile(`^1FAIpQL[A-Za-z0-9_-]{13,}$`)
+	// Edit ID polos tanpa URL, perlu redirect seperti link editor
+	rePlainEditID = regexp.MustCompile(`^[A-Za-z0-9_-]{20,}$`)
+)
+
+func newFormRef(publicID string) FormRef {
+	base := googleFormsBase + "/d/e/" + publicID
+	return FormRef{
+		ID:          publicID,
+		ViewformURL: base + "/viewform",
+		ResponseURL: base + "/formResponse",
+	}
+}
+
+// resolveFormURL menerima link viewform, edit, prefilled, formResponse
+// atau forms.gle lalu mengembalikan FormRef kanonik. Link edit dan
+// forms.gle di-resolve lewat redirect (butuh network).
+func resolveFormURL(raw string) (FormRef, error) {
+	ref, redirect, err := parseFormURL(raw)
+	if err != nil || redirect == "" {
+		return ref, err
+	}
+	return followFormRedirect(redirect)
+}
+
+// parseFormURL: bagian resolveFormURL tanpa network. Jika link perlu
+// di-resolve lewat redirect, FormRef kosong dan redirect berisi link yang
+// harus dibuka.
+func parseFormURL(raw string) (FormRef, string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		// Izinkan form ID polos
+		if rePlainFormID.MatchString(raw) {
+			return newFormRef(raw), "", nil
+		}
+		if rePlainEditID.MatchString(raw) {
+			return FormRef{}, googleFormsBase + "/d/" + raw + "/viewform", nil
+		}
+		return FormRef{}, "", newScrapeError(ErrCodeInvalidURL, "form_url is not a valid URL or form ID", err)
+	}
+
+	if m := rePublicFormPath.FindStringSubmatch(u.Path); m != nil {
+		return newFormRef(m[1]), "", nil
+	}
+
+	host := strings.ToLower(u.Host)
+	switch {
+	case host == "forms.gle" || host == "goo.gl":
+		return FormRef{}, raw, nil
+	case strings.HasSuffix(host, "docs.google.com") || host == formsHost():
+		if m := reEditFormPath.FindStringSubmatch(u.Path); m != nil {
+			return FormRef{}, googleFormsBase + "/d/" + m[1] + "/viewform", nil
+		}
+	}
+
+	return FormRef{}, "", newScrapeError(ErrCodeInvalidURL, "unrecognized Google Form URL: "+raw, nil)
+}
+
+// resolvedLinks: link forms.gle / edit -> public ID yang sudah pernah
+// di-resolve. Pasangan ini tidak berubah, jadi redirect cukup diikuti
+// sekali dan cache skema tetap berguna untuk link pendek. Dikosongkan
+// jika melewati maxResolvedLinks.
+var (
+	resolvedLinksMu sync.Mutex
+	resolvedLinks   = make(map[string]string)
+)
+
+const maxResolvedLinks = 4096
+
+// followFormRedirect me-resolve link lewat resolvedLinks, atau lewat
+// network jika belum pernah (lihat fetchFormRedirect).
+func followFormRedirect(link string) (FormRef, error) {
+	resolvedLinksMu.Lock()
+	id, ok := resolvedLinks[link]
+	resolvedLinksMu.Unlock()
+	if ok {
+		return newFormRef(id), nil
+	}
+
+	ref, err := fetchFormRedirect(link)
+	if err != nil {
+		return FormRef{}, err
+	}
+	resolvedLinksMu.Lock()
+	if len(resolvedLinks) >= maxResolvedLinks {
+		resolvedLinks = make(map[string]string)
+	}
+	resolvedLinks[link] = ref.ID
+	resolvedLinksMu.Unlock()
+	return ref, nil
+}
+
+// fetchFormRedirect membuka link lalu membaca public ID dari URL akhir
+// setelah redirect (atau dari action form di HTML jika tidak redirect).
+func fetchFormRedirect(link string) (FormRef, error) {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return FormRef{}, newScrapeError(ErrCodeInvalidURL, "cannot build request", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	resp, err := fastClient.Do(req)
+	if err != nil {
+		return FormRef{}, newScrapeError(ErrCodeUpstream, "cannot resolve form link", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return FormRef{}, &ScrapeError{Code: ErrCodeFormNotFound, Message: "form link does not exist", UpstreamStatus: resp.StatusCode}
+	}
+	if m := rePublicFormPath.FindStringSubmatch(resp.Request.URL.Path); m != nil {
+		io.Copy(io.Discard, resp.Body)
+		return newFormRef(m[1]), nil
+	}
+	if resp.Request.URL.Host == "accounts.google.com" {
+		return FormRef{}, newScrapeError(ErrCodeSignInRequired, "form link redirects to Google sign-in", nil)
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if id := formIDFromHTML(string(body)); id != "" {
+		return newFormRef(id), nil
+	}
+	return FormRef{}, &ScrapeError{Code: ErrCodeInvalidURL, Message: "link does not resolve to a Google Form", UpstreamStatus: resp.StatusCode}
+}
+
+// formIDFromHTML mengambil public form ID dari atribut action form.
+func formIDFromHTML(content string) string {
+	if m := reFormAction.FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseFormURL(t *testing.T) {
+	const pub = "1FAIpQLSdExamplePublicFormID"
+	const edit = "1aBcDeFgHiJkLmNoPqRsTuVwXyZ"
+	cases := []struct {
+		raw      string
+		id       string
+		redirect string
+	}{
+		{raw: "https://docs.google.com/forms/d/e/" + pub + "/viewform?usp=sf_link", id: pub},
+		{raw: "  https://docs.google.com/forms/d/e/" + pub + "/formResponse  ", id: pub},
+		{raw: "https://docs.google.com/forms/u/1/d/e/" + pub + "/viewform?entry.11=A", id: pub},
+		{raw: pub, id: pub},
+		{raw: "https://docs.google.com/forms/d/" + edit + "/edit", redirect: googleFormsBase + "/d/" + edit + "/viewform"},
+		{raw: edit, redirect: googleFormsBase + "/d/" + edit + "/viewform"},
+		{raw: "https://forms.gle/AbC123", redirect: "https://forms.gle/AbC123"},
+	}
+	for _, c := range cases {
+		ref, redirect, err := parseFormURL(c.raw)
+		if err != nil {
+			t.Errorf("%q: %v", c.raw, err)
+			continue
+		}
+		if ref.ID != c.id || redirect != c.redirect {
+			t.Errorf("%q: got id %q redirect %q, want id %q redirect %q", c.raw, ref.ID, redirect, c.id, c.redirect)
+		}
+		if c.id != "" && ref.ResponseURL != googleFormsBase+"/d/e/"+c.id+"/formResponse" {
+			t.Errorf("%q: response url = %s", c.raw, ref.ResponseURL)
+		}
+	}
+
+	for _, raw := range []string{"", "abc", "https://example.com/forms/x", "https://docs.google.com/spreadsheets/d/abc"} {
+		if _, _, err := parseFormURL(raw); err == nil {
+			t.Errorf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestFollowFormRedirectIsMemoized(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/forms/d/editMemoID/viewform":
+			atomic.AddInt32(&hits, 1)
+			http.Redirect(w, r, "/forms/d/e/1FAIpQLSmemo/viewform", http.StatusFound)
+		default:
+			w.Write([]byte("<html></html>"))
+		}
+	}))
+	defer srv.Close()
+	prev := googleFormsBase
+	googleFormsBase = srv.URL + "/forms"
+	defer func() { googleFormsBase = prev }()
+
+	for i := 0; i < 3; i++ {
+		ref, err := resolveFormURL(srv.URL + "/forms/d/editMemoID/edit")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref.ID != "1FAIpQLSmemo" {
+			t.Fatalf("ref.ID = %q, want 1FAIpQLSmemo", ref.ID)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("edit link fetched %d times, want 1", hits)
+	}
+}
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// --- Models Validator ---
+
+// Violation: satu pelanggaran aturan form pada satu baris jawaban.
+// Level "error" membuat baris tidak dikirim, "warning" hanya dilaporkan.
+type Violation struct {
+	EntryID  int64  `json:"entry_id,omitempty"`
+	Question string `json:"question,omitempty"`
+	Key      string `json:"key,omitempty"` // key jawaban yang tidak dikenali
+	Level    string `json:"level"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+type RowViolations struct {
+	Row        int         `json:"row"`
+	Violations []Violation `json:"violations"`
+}
+
+const (
+	violationError   = "error"
+	violationWarning = "warning"
+)
+
+func hasBlockingViolation(vs []Violation) bool {
+	for _, v := range vs {
+		if v.Level == violationError {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Value Helpers ---
+
+// answerStrings menormalkan satu jawaban menjadi list string, sama seperti
+// yang nanti dikirim ke Google (array untuk checkbox, satu nilai lainnya).
+func answerStrings(val interface{}) []string {
+	var out []string
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, sub := range v {
+			if sub != nil {
+				out = append(out, fmt.Sprintf("%v", sub))
+			}
+		}
+	case []string:
+		out = v
+	default:
+		out = []string{fmt.Sprintf("%v", v)}
+	}
+
+	// String kosong dianggap tidak dijawab
+	nonEmpty := out[:0:0]
+	for _, s := range out {
+		if strings.TrimSpace(s) != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return nonEmpty
+}
+
+var (
+	dateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+	timeLayouts = []string{"15:04", "15:04:05"}
+)
+
+// parseDateAnswer menerima ISO-8601 (tanggal saja atau tanggal+jam),
+// didahului layout Go tambahan dari request jika ada.
+func parseDateAnswer(s string, extraLayouts []string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range append(append([]string{}, extraLayouts...), dateLayouts...) {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tanggal %q tidak valid, gunakan format YYYY-MM-DD atau YYYY-MM-DDTHH:MM", s)
+}
+
+// parseTimeAnswer menerima HH:MM atau HH:MM:SS, atau tanggal+jam
+// lengkap (komponen jamnya yang dipakai).
+func parseTimeAnswer(s string, extraLayouts []string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range append(append([]string{}, extraLayouts...), timeLayouts...) {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := parseDateAnswer(s, nil); err == nil && strings.ContainsAny(s, "T ") {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("waktu %q tidak valid, gunakan format HH:MM atau HH:MM:SS", s)
+}
+
+// parseDurationAnswer menerima H:MM:SS (jam boleh > 24) atau durasi Go
+// seperti "1h30m".
+func parseDurationAnswer(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) == 2 || len(parts) == 3 {
+		var total time.Duration
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		valid := true
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 || (i > 0 && n > 59) {
+				valid = false
+				break
+			}
+			total += time.Duration(n) * units[i]
+		}
+		if valid {
+			return total, nil
+		}
+	}
+	return 0, fmt.Errorf("durasi %q tidak valid, gunakan format H:MM:SS atau 1h30m", s)
+}
+
+// --- Validation ---
+
+// validateAnswers memeriksa jawaban satu baris terhadap skema form.
+// reached (opsional) membatasi pertanyaan yang dicek ke halaman yang dilalui.
+// layouts = layout Go tambahan untuk jawaban tanggal/waktu.
+func validateAnswers(answers map[int64]interface{}, questions []QuestionItem, reached map[int64]bool, layouts []string) []Violation {
+	var vs []Violation
+	for _, q := range questions {
+		if reached != nil && !reached[q.ID] {
+			continue
+		}
+		// File upload tidak bisa diisi lewat injector (dan tidak ada di
+		// schema jawaban), jadi "wajib" di sini tidak memblokir baris.
+		if q.Kind == KindFileUpload {
+			continue
+		}
+		if len(q.Rows) > 0 {
+			for _, row := range q.Rows {
+				vs = append(vs, validateValue(q, row.ID, row.Label, answers[row.ID], layouts)...)
+			}
+			continue
+		}
+		vs = append(vs, validateValue(q, q.ID, "", answers[q.ID], layouts)...)
+	}
+	return vs
+}
+
+func validateValue(q QuestionItem, entryID int64, rowLabel string, val interface{}, layouts []string) []Violation {
+	label := q.Text
+	if rowLabel != "" {
+		label = q.Text + " [" + rowLabel + "]"
+	}
+	fail := func(code, format string, args ...interface{}) Violation {
+		return Violation{
+			EntryID:  entryID,
+			Question: label,
+			Level:    violationError,
+			Code:     code,
+			Message:  fmt.Sprintf(format, args...),
+		}
+	}
+
+	values := answerStrings(val)
+	if len(values) == 0 {
+		if q.Required {
+			return []Violation{fail("required", "pertanyaan wajib diisi")}
+		}
+		return nil
+	}
+
+	var vs []Violation
+	if !q.Kind.IsMultiValue() && len(values) > 1 {
+		vs = append(vs, fail("multiple_values", "hanya boleh satu jawaban, diterima %d", len(values)))
+	}
+
+	switch {
+	case q.Kind.IsChoice():
+		for _, s := range values {
+			if containsString(q.Options, s) || (q.HasOther && s == otherOptionValue) {
+				continue
+			}
+			if q.Kind == KindLinearScale && q.Scale != nil {
+				vs = append(vs, fail("out_of_range", "nilai %q di luar skala %d-%d", s, q.Scale.Min, q.Scale.Max))
+			} else {
+				vs = append(vs, fail("invalid_option", "%q bukan salah satu opsi", s))
+			}
+		}
+	case q.Kind == KindDate, q.Kind == KindTime:
+		code := "invalid_date"
+		if q.Kind == KindTime {
+			code = "invalid_time"
+		}
+		for _, s := range values {
+			if _, _, err := encodeDateTimeAnswer(q, entryID, s, layouts); err != nil {
+				vs = append(vs, fail(code, "%s", err.Error()))
+			}
+		}
+	}
+
+	for _, rule := range q.Validation {
+		if rule.Type == "checkbox" {
+			if msg := checkCardinality(rule, len(values)); msg != "" {
+				vs = append(vs, fail("cardinality", "%s", ruleMessage(rule, msg)))
+			}
+			continue
+		}
+		for _, s := range values {
+			if msg := checkTextRule(rule, s); msg != "" {
+				vs = append(vs, fail("validation_failed", "%s", ruleMessage(rule, msg)))
+			}
+		}
+	}
+
+	return vs
+}
+
+func ruleMessage(rule ValidationRule, fallback string) string {
+	if rule.Message != "" {
+		return rule.Message + " (" + fallback + ")"
+	}
+	return fallback
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func ruleArgFloat(rule ValidationRule, i int) (float64, bool) {
+	if i >= len(rule.Args) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(rule.Args[i], 64)
+	return f, err == nil
+}
+
+// checkCardinality mengecek aturan jumlah pilihan checkbox.
+// Mengembalikan pesan jika melanggar, "" jika lolos.
+func checkCardinality(rule ValidationRule, count int) string {
+	n, ok := ruleArgFloat(rule, 0)
+	if !ok {
+		return ""
+	}
+	switch rule.Operator {
+	case "at_least":
+		if float64(count) < n {
+			return fmt.Sprintf("minimal %v pilihan, diterima %d", n, count)
+		}
+	case "at_most":
+		if float64(count) > n {
+			return fmt.Sprintf("maksimal %v pilihan, diterima %d", n, count)
+		}
+	case "exactly":
+		if float64(count) != n {
+			return fmt.Sprintf("harus tepat %v pilihan, diterima %d", n, count)
+		}
+	}
+	return ""
+}
+
+// checkTextRule mengecek aturan validasi number/text/length/regex.
+// Mengembalikan pesan jika melanggar, "" jika lolos.
+func checkTextRule(rule ValidationRule, s string) string {
+	switch rule.Type {
+	case "number":
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return fmt.Sprintf("%q bukan angka", s)
+		}
+		a, _ := ruleArgFloat(rule, 0)
+		b, _ := ruleArgFloat(rule, 1)
+		ok := true
+		switch rule.Operator {
+		case "gt":
+			ok = f > a
+		case "gte":
+			ok = f >= a
+		case "lt":
+			ok = f < a
+		case "lte":
+			ok = f <= a
+		case "eq":
+			ok = f == a
+		case "ne":
+			ok = f != a
+		case "between":
+			ok = f >= a && f <= b
+		case "not_between":
+			ok = f < a || f > b
+		case "whole_number":
+			ok = f == float64(int64(f))
+		}
+		if !ok {
+			return fmt.Sprintf("angka %v tidak memenuhi %s %s", f, rule.Operator, strings.Join(rule.Args, ","))
+		}
+	case "text":
+		arg := ""
+		if len(rule.Args) > 0 {
+			arg = rule.Args[0]
+		}
+		switch rule.Operator {
+		case "contains":
+			if !strings.Contains(s, arg) {
+				return fmt.Sprintf("harus mengandung %q", arg)
+			}
+		case "not_contains":
+			if strings.Contains(s, arg) {
+				return fmt.Sprintf("tidak boleh mengandung %q", arg)
+			}
+		case "email":
+			if _, err := mail.ParseAddress(s); err != nil {
+				return fmt.Sprintf("%q bukan email valid", s)
+			}
+		case "url":
+			if u, err := url.ParseRequestURI(s); err != nil || u.Host == "" {
+				return fmt.Sprintf("%q bukan URL valid", s)
+			}
+		}
+	case "length":
+		n, ok := ruleArgFloat(rule, 0)
+		if !ok {
+			return ""
+		}
+		length := utf8.RuneCountInString(s)
+		if rule.Operator == "max_length" && float64(length) > n {
+			return fmt.Sprintf("maksimal %v karakter, diterima %d", n, length)
+		}
+		if rule.Operator == "min_length" && float64(length) < n {
+			return fmt.Sprintf("minimal %v karakter, diterima %d", n, length)
+		}
+	case "regex":
+		if len(rule.Args) == 0 {
+			return ""
+		}
+		pattern := rule.Args[0]
+		if rule.Operator == "matches" || rule.Operator == "not_matches" {
+			pattern = "^(?:" + pattern + ")$"
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			// Regex dari Google (RE2) mestinya valid, abaikan jika tidak
+			return ""
+		}
+		found := re.MatchString(s)
+		if (rule.Operator == "contains" || rule.Operator == "matches") && !found {
+			return fmt.Sprintf("tidak cocok dengan pola %q", rule.Args[0])
+		}
+		if (rule.Operator == "not_contains" || rule.Operator == "not_matches") && found {
+			return fmt.Sprintf("tidak boleh cocok dengan pola %q", rule.Args[0])
+		}
+	}
+	return ""
+}
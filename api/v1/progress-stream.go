@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ======================================================
+// Progress Streaming (NDJSON / SSE)
+// ======================================================
+
+// Opt-in untuk handler yang lama (injector, factory): satu event per baris
+// / task yang selesai, lalu event "summary" berisi response lengkap.
+// Dipilih lewat field "stream" di body, atau header Accept
+// application/x-ndjson / text/event-stream.
+const (
+	StreamNDJSON = "ndjson"
+	StreamSSE    = "sse"
+)
+
+// StreamEvent: bentuk satu baris NDJSON. Untuk SSE, Event menjadi field
+// "event:" dan Data menjadi "data:".
+type StreamEvent struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// streamMode menentukan mode streaming dari field request (prioritas)
+// atau header Accept. "" = response JSON biasa.
+func streamMode(requested string, r *http.Request) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "":
+	case StreamNDJSON, "jsonl":
+		return StreamNDJSON, true
+	case StreamSSE, "event-stream":
+		return StreamSSE, true
+	default:
+		return "", false
+	}
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/event-stream"):
+		return StreamSSE, true
+	case strings.Contains(accept, "application/x-ndjson"):
+		return StreamNDJSON, true
+	}
+	return "", true
+}
+
+// progressStream menulis event dan langsung flush. Aman dipanggil dari
+// banyak goroutine.
+type progressStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	sse     bool
+}
+
+func newProgressStream(w http.ResponseWriter, mode string) *progressStream {
+	s := &progressStream{w: w, sse: mode == StreamSSE}
+	s.flusher, _ = w.(http.Flusher)
+	if s.sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // matikan buffering proxy
+	w.WriteHeader(http.StatusOK)
+	s.flush()
+	return s
+}
+
+func (s *progressStream) send(event string, data interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sse {
+		payload, _ := json.Marshal(data)
+		s.w.Write([]byte("event: " + event + "\ndata: "))
+		s.w.Write(payload)
+		s.w.Write([]byte("\n\n"))
+	} else {
+		json.NewEncoder(s.w).Encode(StreamEvent{Event: event, Data: data})
+	}
+	s.flush()
+}
+
+func (s *progressStream) flush() {
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
@@ -24,6 +24,19 @@ type FormSaveState struct {
 	EntryIDs      []int64          `json:"entry_ids"`
 	// Field Baru: Menyimpan peta "Pertanyaan" -> "ID"
 	EntryMappings map[string]int64 `json:"entry_mappings"` 
+	// Skema pertanyaan lengkap (tipe, opsi, baris grid) untuk injector
+	Questions []QuestionItem `json:"questions,omitempty"`
+	// Struktur halaman/section (urut), tiap halaman berisi entry ID-nya
+	Pages []FormPage `json:"pages,omitempty"`
+	// Gambar/video di form (konteks untuk render teks form)
+	Media []MediaItem `json:"media,omitempty"`
+	// Hash struktur form saat di-scrape, untuk deteksi saves basi
+	SchemaHash string `json:"schema_hash,omitempty"`
+	// Pengaturan form (email, sign-in, status menerima respon)
+	Settings *FormSettings `json:"settings,omitempty"`
+	// Sumber saves jika bukan hasil scrape Google Form (surveyjs, qualtrics,
+	// yaml). Saves hasil import hanya untuk generate, tidak bisa diinjeksi.
+	Source string `json:"source,omitempty"`
 }
 
 // =====================
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"datafact/internal/fakeform"
+)
+
+// formFlags: -form boleh diulang, format "<id>=<file.json>" atau
+// "<file.json>" (ID = nama file tanpa ekstensi).
+type formFlags []string
+
+func (f *formFlags) String() string     { return strings.Join(*f, ",") }
+func (f *formFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	var forms formFlags
+	addr := flag.String("addr", ":9090", "listen address")
+	closed := flag.Bool("closed", false, "semua form tidak menerima respon")
+	signIn := flag.Bool("sign-in", false, "semua form wajib login")
+	email := flag.Bool("collect-email", false, "semua form meminta email responden")
+	flag.Var(&forms, "form", "definisi form: <id>=<FB_PUBLIC_LOAD_DATA_.json> (boleh diulang)")
+	flag.Parse()
+
+	if len(forms) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: fakeform -form <id>=<file.json> [-form ...] [-addr :9090]")
+		os.Exit(2)
+	}
+
+	srv := fakeform.New()
+	for _, spec := range forms {
+		id, path := "", spec
+		if i := strings.Index(spec, "="); i >= 0 {
+			id, path = spec[:i], spec[i+1:]
+		}
+		if id == "" {
+			id = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = srv.AddForm(fakeform.Form{
+			ID:            id,
+			LoadData:      data,
+			Closed:        *closed,
+			RequireSignIn: *signIn,
+			CollectEmail:  *email,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("form %s: http://localhost%s/forms/d/e/%s/viewform", id, *addr, id)
+	}
+
+	log.Printf("Fake form server on %s (set DATAFACT_FORMS_BASE_URL=http://localhost%s/forms)", *addr, *addr)
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	golang.org/x/oauth2 v0.34.0
 	google.golang.org/api v0.216.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
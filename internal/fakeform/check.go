@@ -0,0 +1,287 @@
+package fakeform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Definisi Form ---
+
+// Tipe item FB_PUBLIC_LOAD_DATA_ (lihat itemType* di api/v1).
+const (
+	itemTypeMultipleChoice = 2
+	itemTypeDropdown       = 3
+	itemTypeCheckbox       = 4
+	itemTypeLinearScale    = 5
+	itemTypeGrid           = 7
+	itemTypePageBreak      = 8
+)
+
+const otherOptionValue = "__other_option__"
+
+// entryDef: satu entry yang bisa diisi (baris grid = entry sendiri).
+type entryDef struct {
+	ID       int64
+	Title    string
+	Type     int
+	Page     int
+	Required bool
+	Options  []string
+	HasOther bool
+}
+
+type formSchema struct {
+	entries    map[int64]entryDef
+	pages      int
+	emailInput bool // settings email mode 3 = responden mengetik email
+}
+
+// parseSchema membaca definisi minimal dari FB_PUBLIC_LOAD_DATA_ secara
+// independen dari scrapper, supaya bug parsing di scrapper tetap
+// tertangkap saat kiriman diperiksa.
+func parseSchema(loadData json.RawMessage) (formSchema, error) {
+	var raw []interface{}
+	if err := json.Unmarshal(loadData, &raw); err != nil {
+		return formSchema{}, fmt.Errorf("invalid FB_PUBLIC_LOAD_DATA_: %v", err)
+	}
+	lvl1 := list(raw, 1)
+	items, ok := at(lvl1, 1).([]interface{})
+	if !ok {
+		return formSchema{}, fmt.Errorf("FB_PUBLIC_LOAD_DATA_ has no item list at [1][1]")
+	}
+
+	schema := formSchema{entries: make(map[int64]entryDef), pages: 1}
+	if mode, ok := num(list(lvl1, 10), 6); ok && mode == 3 {
+		schema.emailInput = true
+	}
+	for _, it := range items {
+		item, _ := it.([]interface{})
+		itemType, ok := num(item, 3)
+		if !ok {
+			continue
+		}
+		if itemType == itemTypePageBreak {
+			schema.pages++
+			continue
+		}
+		title, _ := at(item, 1).(string)
+		for _, e := range list(item, 4) {
+			entry, _ := e.([]interface{})
+			id, ok := num(entry, 0)
+			if !ok {
+				continue
+			}
+			def := entryDef{
+				ID:       id,
+				Title:    title,
+				Type:     int(itemType),
+				Page:     schema.pages - 1,
+				Required: flag(entry, 2),
+			}
+			for _, o := range list(entry, 1) {
+				opt, _ := o.([]interface{})
+				if flag(opt, 4) {
+					def.HasOther = true
+					continue
+				}
+				if label, ok := at(opt, 0).(string); ok {
+					def.Options = append(def.Options, label)
+				}
+			}
+			schema.entries[id] = def
+		}
+	}
+	return schema, nil
+}
+
+func at(arr []interface{}, i int) interface{} {
+	if i < 0 || i >= len(arr) {
+		return nil
+	}
+	return arr[i]
+}
+
+func list(arr []interface{}, i int) []interface{} {
+	l, _ := at(arr, i).([]interface{})
+	return l
+}
+
+func num(arr []interface{}, i int) (int64, bool) {
+	f, ok := at(arr, i).(float64)
+	return int64(f), ok
+}
+
+func flag(arr []interface{}, i int) bool {
+	n, _ := num(arr, i)
+	return n == 1
+}
+
+func (d entryDef) isChoice() bool {
+	switch d.Type {
+	case itemTypeMultipleChoice, itemTypeDropdown, itemTypeCheckbox, itemTypeLinearScale, itemTypeGrid:
+		return true
+	}
+	return false
+}
+
+// --- Pemeriksaan Kiriman ---
+
+// checkSubmission membaca body formResponse (partialResponse + field
+// entry.<ID>) lalu memeriksanya terhadap definisi form. missingRequired
+// = jumlah pertanyaan wajib yang kosong.
+func checkSubmission(f Form, schema formSchema, form url.Values) (Submission, int) {
+	sub := Submission{
+		FormID:     f.ID,
+		ReceivedAt: time.Now(),
+		Answers:    make(map[string][]string),
+		Other:      make(map[string]string),
+		Email:      form.Get("emailAddress"),
+	}
+	var errs []string
+	addErr := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if form.Get("fbzx") != f.Fbzx {
+		addErr("fbzx: token mismatch")
+	}
+
+	// pageHistory: "0,2,3"; kosong = hanya halaman pertama
+	sub.PageHistory = []int{0}
+	if ph := strings.TrimSpace(form.Get("pageHistory")); ph != "" {
+		sub.PageHistory = nil
+		for _, part := range strings.Split(ph, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 0 || n >= schema.pages {
+				addErr("pageHistory: invalid page %q", part)
+				continue
+			}
+			sub.PageHistory = append(sub.PageHistory, n)
+		}
+		if len(sub.PageHistory) == 0 || sub.PageHistory[0] != 0 {
+			addErr("pageHistory: must start at page 0")
+		}
+	}
+	visited := make(map[int]bool)
+	for _, p := range sub.PageHistory {
+		visited[p] = true
+	}
+
+	// partialResponse: [[[null, id, [values], 0], ...], email, fbzx]
+	if pr := form.Get("partialResponse"); pr != "" {
+		var partial []interface{}
+		if err := json.Unmarshal([]byte(pr), &partial); err != nil {
+			addErr("partialResponse: %v", err)
+		}
+		for _, r := range list(partial, 0) {
+			resp, _ := r.([]interface{})
+			id, ok := num(resp, 1)
+			if !ok {
+				addErr("partialResponse: entry without ID")
+				continue
+			}
+			key := strconv.FormatInt(id, 10)
+			for _, v := range list(resp, 2) {
+				sub.Answers[key] = append(sub.Answers[key], fmt.Sprint(v))
+			}
+		}
+		if email, ok := at(partial, 1).(string); ok && sub.Email == "" {
+			sub.Email = email
+		}
+		if token, ok := at(partial, 2).(string); ok && token != f.Fbzx {
+			addErr("partialResponse: fbzx mismatch")
+		}
+	}
+
+	// Field entry.<ID> biasa (komponen tanggal/waktu dilewati)
+	for field, values := range form {
+		if !strings.HasPrefix(field, "entry.") {
+			continue
+		}
+		key := strings.TrimPrefix(field, "entry.")
+		if id := strings.TrimSuffix(key, ".other_option_response"); id != key {
+			sub.Other[id] = strings.Join(values, "")
+			continue
+		}
+		if strings.Contains(key, "_") {
+			continue
+		}
+		sub.Answers[key] = append(sub.Answers[key], values...)
+	}
+
+	var ids []string
+	for key := range sub.Answers {
+		ids = append(ids, key)
+	}
+	sort.Strings(ids)
+	for _, key := range ids {
+		id, err := strconv.ParseInt(key, 10, 64)
+		def, ok := schema.entries[id]
+		if err != nil || !ok {
+			addErr("entry.%s: unknown question", key)
+			continue
+		}
+		if !visited[def.Page] {
+			addErr("entry.%s: answered on unvisited page %d", key, def.Page)
+		}
+		if !def.isChoice() {
+			continue
+		}
+		for _, v := range sub.Answers[key] {
+			switch {
+			case v == otherOptionValue && def.HasOther:
+				if sub.Other[key] == "" {
+					addErr("entry.%s: other option chosen without text", key)
+				}
+			case !contains(def.Options, v):
+				addErr("entry.%s: %q is not an option of %q", key, v, def.Title)
+			}
+		}
+	}
+
+	if (f.CollectEmail || schema.emailInput) && sub.Email == "" {
+		addErr("emailAddress: email is required")
+	}
+
+	// Pertanyaan wajib pada halaman yang dilalui
+	var required []int64
+	for id, def := range schema.entries {
+		if def.Required && visited[def.Page] && !answered(sub.Answers[strconv.FormatInt(id, 10)]) {
+			required = append(required, id)
+		}
+	}
+	sort.Slice(required, func(i, j int) bool { return required[i] < required[j] })
+	for _, id := range required {
+		addErr("entry.%d: required question %q not answered", id, schema.entries[id].Title)
+	}
+
+	sub.Errors = errs
+	sub.Accepted = len(errs) == 0
+	if len(sub.Other) == 0 {
+		sub.Other = nil
+	}
+	return sub, len(required)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func answered(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
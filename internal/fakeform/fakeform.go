@@ -0,0 +1,291 @@
+// Package fakeform adalah server pengganti Google Form untuk uji offline.
+// Server melayani halaman viewform berisi FB_PUBLIC_LOAD_DATA_ yang bisa
+// diatur, menerima POST formResponse, memeriksa kiriman terhadap definisi
+// form, lalu mencatat setiap kiriman.
+//
+// Arahkan service ke server ini lewat env DATAFACT_FORMS_BASE_URL, misal
+// "http://localhost:9090/forms", maka scrape & inject memakai server ini
+// sebagai ganti docs.google.com.
+package fakeform
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Form: definisi satu form palsu.
+type Form struct {
+	ID string
+	// LoadData: array FB_PUBLIC_LOAD_DATA_ mentah (format sama dengan
+	// testdata/forms/*.json di api/v1).
+	LoadData json.RawMessage
+	// Fbzx: token yang disisipkan di HTML dan wajib dikirim balik.
+	// Kosong = dibuat otomatis.
+	Fbzx string
+
+	Closed        bool // viewform & formResponse redirect ke closedform
+	RequireSignIn bool // halaman ditandai wajib login, kiriman ditolak
+	CollectEmail  bool // form punya input email responden (wajib diisi)
+}
+
+// Submission: satu kiriman yang diterima (atau ditolak) server.
+type Submission struct {
+	FormID      string              `json:"form_id"`
+	ReceivedAt  time.Time           `json:"received_at"`
+	Accepted    bool                `json:"accepted"`
+	Errors      []string            `json:"errors,omitempty"`
+	Email       string              `json:"email,omitempty"`
+	PageHistory []int               `json:"page_history"`
+	Answers     map[string][]string `json:"answers"` // key = entry ID
+	Other       map[string]string   `json:"other,omitempty"`
+}
+
+type formState struct {
+	form        Form
+	schema      formSchema
+	page        []byte
+	etag        string
+	submissions []Submission
+}
+
+// Server menyimpan form & kiriman di memori. Aman dipakai concurrent.
+type Server struct {
+	mu    sync.Mutex
+	forms map[string]*formState
+}
+
+func New() *Server {
+	return &Server{forms: make(map[string]*formState)}
+}
+
+// AddForm mendaftarkan (atau mengganti) form. Kiriman lama form dengan ID
+// yang sama dihapus.
+func (s *Server) AddForm(f Form) error {
+	if f.ID == "" {
+		return fmt.Errorf("form ID is required")
+	}
+	schema, err := parseSchema(f.LoadData)
+	if err != nil {
+		return fmt.Errorf("form %s: %v", f.ID, err)
+	}
+	if f.Fbzx == "" {
+		f.Fbzx = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	page := renderViewform(f)
+	sum := sha1.Sum(page)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forms[f.ID] = &formState{
+		form:   f,
+		schema: schema,
+		page:   page,
+		etag:   `"` + hex.EncodeToString(sum[:8]) + `"`,
+	}
+	return nil
+}
+
+// Submissions mengembalikan salinan kiriman untuk satu form.
+func (s *Server) Submissions(formID string) []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.forms[formID]
+	if !ok {
+		return nil
+	}
+	return append([]Submission(nil), st.submissions...)
+}
+
+// Reset menghapus semua kiriman form tanpa menghapus definisinya.
+func (s *Server) Reset(formID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.forms[formID]; ok {
+		st.submissions = nil
+	}
+}
+
+func (s *Server) lookup(formID string) (*formState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.forms[formID]
+	return st, ok
+}
+
+// =====================
+// Routing
+// =====================
+
+// ServeHTTP melayani path ala Google Form:
+//
+//	GET  /forms/d/e/<id>/viewform
+//	POST /forms/d/e/<id>/formResponse
+//	GET  /forms/d/e/<id>/closedform
+//	GET  /forms/d/e/<id>/submissions   (khusus fakeform, JSON)
+//	DELETE /forms/d/e/<id>/submissions (khusus fakeform, reset)
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 5 || parts[0] != "forms" || parts[1] != "d" || parts[2] != "e" {
+		http.NotFound(w, r)
+		return
+	}
+	formID, action := parts[3], parts[4]
+	st, ok := s.lookup(formID)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch action {
+	case "viewform":
+		s.serveViewform(w, r, st)
+	case "formResponse":
+		if r.Method != http.MethodPost {
+			http.Error(w, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		s.serveFormResponse(w, r, st)
+	case "closedform":
+		writeHTML(w, http.StatusOK, closedPage)
+	case "submissions":
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(s.Submissions(formID))
+		case http.MethodDelete:
+			s.Reset(formID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "use GET or DELETE", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveViewform(w http.ResponseWriter, r *http.Request, st *formState) {
+	if st.form.Closed {
+		http.Redirect(w, r, closedURL(st.form.ID), http.StatusFound)
+		return
+	}
+	// ETag agar conditional GET cache skema (form-cache.go) bisa diuji
+	w.Header().Set("ETag", st.etag)
+	if r.Header.Get("If-None-Match") == st.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(st.page)
+}
+
+func (s *Server) serveFormResponse(w http.ResponseWriter, r *http.Request, st *formState) {
+	if st.form.Closed {
+		http.Redirect(w, r, closedURL(st.form.ID), http.StatusFound)
+		return
+	}
+	if st.form.RequireSignIn {
+		// Google mengarahkan ke halaman login; offline cukup halamannya
+		writeHTML(w, http.StatusOK, signInPage)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub, missingRequired := checkSubmission(st.form, st.schema, r.PostForm)
+	s.mu.Lock()
+	st.submissions = append(st.submissions, sub)
+	s.mu.Unlock()
+
+	switch {
+	case sub.Accepted:
+		writeHTML(w, http.StatusOK, confirmationPage)
+	case missingRequired == len(sub.Errors):
+		// Google menampilkan ulang form dengan pesan di bawah pertanyaan
+		writeHTML(w, http.StatusOK, requiredPage(sub.Errors))
+	default:
+		writeHTML(w, http.StatusBadRequest, errorPage(sub.Errors))
+	}
+}
+
+func closedURL(formID string) string {
+	return "/forms/d/e/" + formID + "/closedform"
+}
+
+// =====================
+// HTML
+// =====================
+
+func renderViewform(f Form) []byte {
+	bodyAttr := ""
+	if f.RequireSignIn {
+		bodyAttr = ` data-sign-in-to-continue="true"`
+	}
+	emailInput := ""
+	if f.CollectEmail {
+		emailInput = `<input type="email" name="emailAddress" autocomplete="email">`
+	}
+	return []byte(fmt.Sprintf(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Fake Form</title></head>
+<body%s>
+<form action="/forms/d/e/%s/formResponse" method="POST">
+%s
+<input type="hidden" name="fbzx" value="%s">
+</form>
+<script type="text/javascript" nonce="fake">var FB_PUBLIC_LOAD_DATA_ = %s;</script>
+</body></html>
+`, bodyAttr, html.EscapeString(f.ID), emailInput, html.EscapeString(f.Fbzx), f.LoadData))
+}
+
+const confirmationPage = `<!DOCTYPE html>
+<html><head><title>Fake Form</title></head><body>
+<div class="freebirdFormviewerViewResponseConfirmationMessage">Your response has been recorded.</div>
+</body></html>
+`
+
+const closedPage = `<!DOCTYPE html>
+<html><head><title>Fake Form</title></head><body>
+<div class="freebirdFormviewerViewResponseConfirmationMessage">The form Fake Form is no longer accepting responses.</div>
+</body></html>
+`
+
+const signInPage = `<!DOCTYPE html>
+<html><head><title>Sign in - Google Accounts</title></head><body>
+<a href="https://accounts.google.com/ServiceLogin?continue=form">Sign in to continue</a>
+</body></html>
+`
+
+func requiredPage(errs []string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><title>Fake Form</title></head><body>\n")
+	for _, e := range errs {
+		b.WriteString(`<div class="freebirdFormviewerViewItemsItemErrorMessage" role="alert" data-detail="` + html.EscapeString(e) + `">This is a required question</div>` + "\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+func errorPage(errs []string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><title>Error 400 (Bad Request)</title></head><body>\n<ul>\n")
+	for _, e := range errs {
+		b.WriteString("<li>" + html.EscapeString(e) + "</li>\n")
+	}
+	b.WriteString("</ul>\n</body></html>\n")
+	return b.String()
+}
+
+func writeHTML(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
@@ -15,8 +15,15 @@ func main() {
 	// Kita memanggil fungsi-fungsi dari package handler
 	http.HandleFunc("/api/v1/persona-filter", handler.Handler)       // Ini fungsi Handler di persona-filter.go
 	http.HandleFunc("/api/v1/form-scrapper", handler.ScrapperHandler) // Ini fungsi di form-scrapper.go
+	http.HandleFunc("/api/v1/form-scrapper-batch", handler.BatchScrapperHandler) // Ini fungsi di form-scrapper-batch.go
+	http.HandleFunc("/api/v1/form-parser", handler.ParserHandler)     // Ini fungsi di form-parser.go
 	http.HandleFunc("/api/v1/form-injector", handler.InjectorHandler) // Ini fungsi di form-injector.go
+	http.HandleFunc("/api/v1/form-injector-job", handler.InjectJobHandler)              // Ini fungsi di form-injector-job.go
+	http.HandleFunc("/api/v1/form-injector-job/cancel", handler.InjectJobCancelHandler) // Ini fungsi di form-injector-job.go
 	http.HandleFunc("/api/v1/datafact-factory", handler.DataFactFactoryHandler) // Ini fungsi di form-injector.go
+	http.HandleFunc("/api/v1/form-renderer", handler.RendererHandler)          // Ini fungsi di form-renderer.go
+	http.HandleFunc("/api/v1/form-schema", handler.SchemaHandler)              // Ini fungsi di form-schema.go
+	http.HandleFunc("/api/v1/form-import", handler.ImportHandler)              // Ini fungsi di form-import.go
 
 	// Tentukan Port (Google Cloud Run mewajibkan ambil dari environment variable PORT)
 	port := os.Getenv("PORT")